@@ -0,0 +1,291 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// BootProfile is the flattened view of the active boot profile of a server, as exposed by the
+// hetzner-robot_boot resource and data source.
+type BootProfile struct {
+	ActiveProfile       string // linux/rescue/...
+	Architecture        string
+	AuthorizedKeys      []string
+	HostKeyFingerprints []string
+	Hostname            string
+	Keyboard            string
+	Language            string
+	OperatingSystem     string
+	Password            string
+	ServerID            int
+	ServerIPv4          string
+	ServerIPv6          string
+}
+
+func bootProfileFromConfig(config *robot.BootConfig) *BootProfile {
+	bootProfile := BootProfile{}
+
+	switch {
+	case config.Rescue != nil && config.Rescue.Active:
+		bootProfile.ActiveProfile = robot.BootProfileRescue
+		bootProfile.Architecture = config.Rescue.Arch.First()
+		bootProfile.AuthorizedKeys = config.Rescue.AuthorizedKeys.Fingerprints()
+		bootProfile.HostKeyFingerprints = config.Rescue.HostKeys.Fingerprints()
+		bootProfile.Keyboard = config.Rescue.Keyboard.First()
+		bootProfile.OperatingSystem = config.Rescue.OS.First()
+		bootProfile.Password = config.Rescue.Password
+		bootProfile.ServerID = config.Rescue.ServerNumber
+		bootProfile.ServerIPv4 = config.Rescue.ServerIP
+		bootProfile.ServerIPv6 = config.Rescue.ServerIPv6Net
+	case config.Linux != nil && config.Linux.Active:
+		bootProfile.ActiveProfile = robot.BootProfileLinux
+		bootProfile.Architecture = config.Linux.Arch.First()
+		bootProfile.AuthorizedKeys = config.Linux.AuthorizedKeys.Fingerprints()
+		bootProfile.HostKeyFingerprints = config.Linux.HostKeys.Fingerprints()
+		bootProfile.Language = config.Linux.Lang.First()
+		bootProfile.OperatingSystem = config.Linux.Dist.First()
+		bootProfile.Password = config.Linux.Password
+		bootProfile.ServerID = config.Linux.ServerNumber
+		bootProfile.ServerIPv4 = config.Linux.ServerIP
+		bootProfile.ServerIPv6 = config.Linux.ServerIPv6Net
+	case config.VNC != nil && config.VNC.Active:
+		bootProfile.ActiveProfile = robot.BootProfileVNC
+		bootProfile.Architecture = config.VNC.Arch.First()
+		bootProfile.Language = config.VNC.Lang.First()
+		bootProfile.OperatingSystem = config.VNC.Dist.First()
+		bootProfile.Password = config.VNC.Password
+		bootProfile.ServerID = config.VNC.ServerNumber
+		bootProfile.ServerIPv4 = config.VNC.ServerIP
+		bootProfile.ServerIPv6 = config.VNC.ServerIPv6Net
+	case config.Windows != nil && config.Windows.Active:
+		bootProfile.ActiveProfile = robot.BootProfileWindows
+		bootProfile.Language = config.Windows.Lang.First()
+		bootProfile.OperatingSystem = config.Windows.Dist.First()
+		bootProfile.Password = config.Windows.Password
+		bootProfile.ServerID = config.Windows.ServerNumber
+		bootProfile.ServerIPv4 = config.Windows.ServerIP
+		bootProfile.ServerIPv6 = config.Windows.ServerIPv6Net
+	case config.Plesk != nil && config.Plesk.Active:
+		bootProfile.ActiveProfile = robot.BootProfilePlesk
+		setPanelProfile(&bootProfile, config.Plesk)
+	case config.CPanel != nil && config.CPanel.Active:
+		bootProfile.ActiveProfile = robot.BootProfileCPanel
+		setPanelProfile(&bootProfile, config.CPanel)
+	}
+
+	return &bootProfile
+}
+
+func setPanelProfile(bootProfile *BootProfile, config *robot.PanelConfig) {
+	bootProfile.Architecture = config.Arch.First()
+	bootProfile.Hostname = config.Hostname
+	bootProfile.Language = config.Lang.First()
+	bootProfile.OperatingSystem = config.Dist.First()
+	bootProfile.Password = config.Password
+	bootProfile.ServerID = config.ServerNumber
+	bootProfile.ServerIPv4 = config.ServerIP
+	bootProfile.ServerIPv6 = config.ServerIPv6Net
+}
+
+// getLastBoot returns the configuration a rescue or linux profile was last activated with. Its ActiveProfile is the
+// profile, whether it is still active or not.
+func (c *HetznerRobotClient) getLastBoot(ctx context.Context, serverID int, profile string) (*BootProfile, error) {
+	bootProfile := BootProfile{ActiveProfile: profile}
+
+	switch profile {
+	case robot.BootProfileRescue:
+		config, err := c.Boot.GetLastRescue(ctx, serverID)
+		if err != nil {
+			return nil, err
+		}
+		bootProfile.Architecture = config.Arch.First()
+		bootProfile.AuthorizedKeys = config.AuthorizedKeys.Fingerprints()
+		bootProfile.HostKeyFingerprints = config.HostKeys.Fingerprints()
+		bootProfile.Keyboard = config.Keyboard.First()
+		bootProfile.OperatingSystem = config.OS.First()
+		bootProfile.ServerID = config.ServerNumber
+	case robot.BootProfileLinux:
+		config, err := c.Boot.GetLastLinux(ctx, serverID)
+		if err != nil {
+			return nil, err
+		}
+		bootProfile.Architecture = config.Arch.First()
+		bootProfile.AuthorizedKeys = config.AuthorizedKeys.Fingerprints()
+		bootProfile.HostKeyFingerprints = config.HostKeys.Fingerprints()
+		bootProfile.Language = config.Lang.First()
+		bootProfile.OperatingSystem = config.Dist.First()
+		bootProfile.ServerID = config.ServerNumber
+	default:
+		return nil, fmt.Errorf("no last configuration of boot profile %q", profile)
+	}
+
+	return &bootProfile, nil
+}
+
+func (c *HetznerRobotClient) getBoot(ctx context.Context, serverID int) (*BootProfile, error) {
+	config, err := c.Boot.Get(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	return bootProfileFromConfig(config), nil
+}
+
+// setBootProfile activates profile.ActiveProfile with the arguments of profile that apply to it (see
+// bootProfileArguments).
+func (c *HetznerRobotClient) setBootProfile(ctx context.Context, serverID int, profile BootProfile) (*BootProfile, error) {
+	config := robot.BootConfig{}
+	var err error
+
+	switch profile.ActiveProfile {
+	case robot.BootProfileLinux:
+		config.Linux, err = c.Boot.ActivateLinux(ctx, serverID, robot.LinuxRequest{
+			Dist:           profile.OperatingSystem,
+			Arch:           profile.Architecture,
+			Lang:           profile.Language,
+			AuthorizedKeys: profile.AuthorizedKeys,
+		})
+	case robot.BootProfileRescue:
+		config.Rescue, err = c.Boot.ActivateRescue(ctx, serverID, robot.RescueRequest{
+			OS:             profile.OperatingSystem,
+			Arch:           profile.Architecture,
+			Keyboard:       profile.Keyboard,
+			AuthorizedKeys: profile.AuthorizedKeys,
+		})
+	case robot.BootProfileVNC:
+		config.VNC, err = c.Boot.ActivateVNC(ctx, serverID, robot.VNCRequest{
+			Dist: profile.OperatingSystem,
+			Arch: profile.Architecture,
+			Lang: profile.Language,
+		})
+	case robot.BootProfileWindows:
+		config.Windows, err = c.Boot.ActivateWindows(ctx, serverID, robot.WindowsRequest{
+			Dist: profile.OperatingSystem,
+			Lang: profile.Language,
+		})
+	case robot.BootProfilePlesk, robot.BootProfileCPanel:
+		request := robot.PanelRequest{
+			Dist:     profile.OperatingSystem,
+			Arch:     profile.Architecture,
+			Lang:     profile.Language,
+			Hostname: profile.Hostname,
+		}
+		if profile.ActiveProfile == robot.BootProfilePlesk {
+			config.Plesk, err = c.Boot.ActivatePlesk(ctx, serverID, request)
+		} else {
+			config.CPanel, err = c.Boot.ActivateCPanel(ctx, serverID, request)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported boot profile %q", profile.ActiveProfile)
+	}
+	if err != nil {
+		if robot.HasErrorCode(err, "BOOT_ALREADY_ENABLED") {
+			return c.getBoot(ctx, serverID)
+		}
+		return nil, err
+	}
+
+	return bootProfileFromConfig(&config), nil
+}
+
+// unsetBootProfile deactivates a profile. A profile that is no longer active, or no longer available, counts as
+// deactivated.
+func (c *HetznerRobotClient) unsetBootProfile(ctx context.Context, serverID int, profile string) error {
+	err := c.Boot.Deactivate(ctx, serverID, profile)
+	if err == nil || robot.IsNotFound(err) {
+		return nil
+	}
+
+	boot, getErr := c.getBoot(ctx, serverID)
+	if getErr == nil && boot.ActiveProfile != profile {
+		tflog.Debug(ctx, "boot profile already inactive", map[string]interface{}{
+			"server_id": serverID,
+			"profile":   profile,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return err
+}
+
+// bootOptions are the values Robot accepts for the arguments of a profile.
+type bootOptions struct {
+	OperatingSystems []string
+	Architectures    []string
+	Languages        []string
+	Keyboards        []string
+}
+
+// bootProfileOptions returns the options of the profiles available for the server. Robot only lists them while a
+// profile is inactive, active profiles report their configured values instead and are left out.
+func bootProfileOptions(config *robot.BootConfig) map[string]bootOptions {
+	options := make(map[string]bootOptions)
+	if config.Rescue != nil && !config.Rescue.Active {
+		options[robot.BootProfileRescue] = bootOptions{OperatingSystems: config.Rescue.OS, Architectures: config.Rescue.Arch, Keyboards: config.Rescue.Keyboard}
+	}
+	if config.Linux != nil && !config.Linux.Active {
+		options[robot.BootProfileLinux] = bootOptions{OperatingSystems: config.Linux.Dist, Architectures: config.Linux.Arch, Languages: config.Linux.Lang}
+	}
+	if config.VNC != nil && !config.VNC.Active {
+		options[robot.BootProfileVNC] = bootOptions{OperatingSystems: config.VNC.Dist, Architectures: config.VNC.Arch, Languages: config.VNC.Lang}
+	}
+	if config.Windows != nil && !config.Windows.Active {
+		options[robot.BootProfileWindows] = bootOptions{OperatingSystems: config.Windows.Dist, Languages: config.Windows.Lang}
+	}
+	if config.Plesk != nil && !config.Plesk.Active {
+		options[robot.BootProfilePlesk] = bootOptions{OperatingSystems: config.Plesk.Dist, Architectures: config.Plesk.Arch, Languages: config.Plesk.Lang}
+	}
+	if config.CPanel != nil && !config.CPanel.Active {
+		options[robot.BootProfileCPanel] = bootOptions{OperatingSystems: config.CPanel.Dist, Architectures: config.CPanel.Arch, Languages: config.CPanel.Lang}
+	}
+	return options
+}
+
+// bootProfileAvailable reports whether the server offers a profile at all.
+func bootProfileAvailable(config *robot.BootConfig, profile string) bool {
+	switch profile {
+	case robot.BootProfileRescue:
+		return config.Rescue != nil
+	case robot.BootProfileLinux:
+		return config.Linux != nil
+	case robot.BootProfileVNC:
+		return config.VNC != nil
+	case robot.BootProfileWindows:
+		return config.Windows != nil
+	case robot.BootProfilePlesk:
+		return config.Plesk != nil
+	case robot.BootProfileCPanel:
+		return config.CPanel != nil
+	}
+	return false
+}
+
+// bootProfiles are the profiles hetzner-robot_boot can activate, in the order of the Robot documentation.
+var bootProfiles = []string{
+	robot.BootProfileRescue,
+	robot.BootProfileLinux,
+	robot.BootProfileVNC,
+	robot.BootProfileWindows,
+	robot.BootProfilePlesk,
+	robot.BootProfileCPanel,
+}
+
+// bootProfileArguments lists the optional hetzner-robot_boot arguments each profile accepts; operating_system
+// applies to all of them. Robot's VNC installation takes no resolution, hetzner-robot_boot rejects it for every
+// profile.
+var bootProfileArguments = map[string][]string{
+	robot.BootProfileRescue:  {"architecture", "authorized_keys", "keyboard"},
+	robot.BootProfileLinux:   {"architecture", "authorized_keys", "language"},
+	robot.BootProfileVNC:     {"architecture", "language"},
+	robot.BootProfileWindows: {"language"},
+	robot.BootProfilePlesk:   {"architecture", "hostname", "language"},
+	robot.BootProfileCPanel:  {"architecture", "hostname", "language"},
+}
+
+// bootProfileRequiredArguments are the arguments Robot has no default for.
+var bootProfileRequiredArguments = map[string][]string{
+	robot.BootProfilePlesk:  {"hostname"},
+	robot.BootProfileCPanel: {"hostname"},
+}
@@ -0,0 +1,41 @@
+package hetznerrobot
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type operationFunc func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// withCallers labels the webservice requests of every operation with the resource type and ID they are sent for,
+// so that logs and read-only refusals name the resource.
+func withCallers(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for name, r := range resources {
+		if r.CreateContext != nil {
+			r.CreateContext = schema.CreateContextFunc(withCaller(name, operationFunc(r.CreateContext)))
+		}
+		if r.ReadContext != nil {
+			r.ReadContext = schema.ReadContextFunc(withCaller(name, operationFunc(r.ReadContext)))
+		}
+		if r.UpdateContext != nil {
+			r.UpdateContext = schema.UpdateContextFunc(withCaller(name, operationFunc(r.UpdateContext)))
+		}
+		if r.DeleteContext != nil {
+			r.DeleteContext = schema.DeleteContextFunc(withCaller(name, operationFunc(r.DeleteContext)))
+		}
+	}
+	return resources
+}
+
+func withCaller(name string, f operationFunc) operationFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		caller := name
+		if id := d.Id(); id != "" {
+			caller += " " + id
+		}
+		return f(robot.ContextWithCaller(ctx, caller), d, meta)
+	}
+}
@@ -0,0 +1,181 @@
+package hetznerrobot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// credentialSources are the provider arguments credentials can come from, by decreasing precedence:
+// username/password, password_file, credential_process and the profile of the config file.
+type credentialSources struct {
+	Username          string
+	Password          string
+	PasswordFile      string
+	CredentialProcess []string
+	Profile           string
+	ConfigFile        string
+}
+
+// credentialProfile is a named table of the config file, e.g.
+//
+//	[prod]
+//	username = "#ws+abcdef"
+//	password_file = "~/.secrets/robot"
+type credentialProfile struct {
+	Username          string   `toml:"username"`
+	Password          string   `toml:"password"`
+	PasswordFile      string   `toml:"password_file"`
+	CredentialProcess []string `toml:"credential_process"`
+}
+
+// processCredentials is the JSON document a credential_process prints on stdout.
+type processCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+var errMissingCredentials = errors.New("no Hetzner Robot webservice credentials found: set username and password " +
+	"(or HETZNERROBOT_USERNAME and HETZNERROBOT_PASSWORD), password_file, credential_process or a profile")
+
+// defaultConfigFile returns ~/.config/hetzner-robot/config.toml, honouring XDG_CONFIG_HOME.
+func defaultConfigFile() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "hetzner-robot", "config.toml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "hetzner-robot", "config.toml")
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+func readPasswordFile(path string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read password file: %w", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func runCredentialProcess(ctx context.Context, command []string) (*processCredentials, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential_process %q failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	credentials := processCredentials{}
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return nil, fmt.Errorf("credential_process %q did not print JSON credentials: %w", command[0], err)
+	}
+	return &credentials, nil
+}
+
+func readProfile(configFile string, name string) (*credentialProfile, error) {
+	configFile, err := expandHome(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := map[string]credentialProfile{}
+	if _, err := toml.DecodeFile(configFile, &profiles); err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %w", configFile, err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in %s", name, configFile)
+	}
+	return &profile, nil
+}
+
+// fillCredentials completes the credentials still missing from the given password file and credential process.
+func fillCredentials(ctx context.Context, username string, password string, passwordFile string, credentialProcess []string) (string, string, error) {
+	if password == "" && passwordFile != "" {
+		filePassword, err := readPasswordFile(passwordFile)
+		if err != nil {
+			return "", "", err
+		}
+		password = filePassword
+	}
+
+	if (username == "" || password == "") && len(credentialProcess) > 0 {
+		tflog.Debug(ctx, "running credential_process", map[string]interface{}{
+			"command": credentialProcess[0],
+		})
+		credentials, err := runCredentialProcess(ctx, credentialProcess)
+		if err != nil {
+			return "", "", err
+		}
+		if username == "" {
+			username = credentials.Username
+		}
+		if password == "" {
+			password = credentials.Password
+		}
+	}
+
+	return username, password, nil
+}
+
+// resolveCredentials returns the webservice username and password, or errMissingCredentials when none of
+// the sources provide them.
+func resolveCredentials(ctx context.Context, sources credentialSources) (string, string, error) {
+	username, password, err := fillCredentials(ctx, sources.Username, sources.Password, sources.PasswordFile, sources.CredentialProcess)
+	if err != nil {
+		return "", "", err
+	}
+
+	if (username == "" || password == "") && sources.Profile != "" {
+		configFile := sources.ConfigFile
+		if configFile == "" {
+			configFile = defaultConfigFile()
+		}
+		profile, err := readProfile(configFile, sources.Profile)
+		if err != nil {
+			return "", "", err
+		}
+		if username == "" {
+			username = profile.Username
+		}
+		if password == "" {
+			password = profile.Password
+		}
+		username, password, err = fillCredentials(ctx, username, password, profile.PasswordFile, profile.CredentialProcess)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if username == "" || password == "" {
+		return "", "", errMissingCredentials
+	}
+	return username, password, nil
+}
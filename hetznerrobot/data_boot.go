@@ -11,17 +11,43 @@ func dataBoot() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceBootRead,
 		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server ID",
+			},
 			// read-only / computed
 			"active_profile": {
 				Type:        schema.TypeString, // Enum should be better (linux/rescue/...)
 				Computed:    true,
-				Description: "Active boot profile",
+				Description: "Active boot profile (`rescue`, `linux`, `vnc`, `windows`, `plesk` or `cpanel`)",
 			},
 			"architecture": {
 				Type:        schema.TypeString, // Enum should be better (amd64/...)
 				Computed:    true,
 				Description: "Active Architecture",
 			},
+			"authorized_keys": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Fingerprints of the SSH keys authorized for the rescue system / Linux installation",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"host_key_fingerprints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "MD5 fingerprints of the SSH host keys of the rescue system / Linux installation. Robot reports no key data, so they can't go into `known_hosts`: compare them with the fingerprint `ssh -o FingerprintHash=md5` shows on the first connection",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hostname of the Plesk or cPanel installation",
+			},
 			"ipv4_address": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -32,6 +58,11 @@ func dataBoot() *schema.Resource {
 				Computed:    true,
 				Description: "Server main IPv6 net address",
 			},
+			"keyboard": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Keyboard layout of the rescue system",
+			},
 			"language": {
 				Type:        schema.TypeString, // Enum should be better (amd64/...)
 				Computed:    true,
@@ -45,14 +76,10 @@ func dataBoot() *schema.Resource {
 			"password": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "Current Rescue System root password / Linux installation password or null",
+				Description: "Current Rescue System root password / installation password or null",
 				Sensitive:   true,
 			},
 		},
-		/*
-			AuthorizedKeys []string		    authorized_key (Array)	Authorized public SSH keys
-			HostKeys []string				host_key (Array)	Host keys
-		*/
 	}
 }
 func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -66,8 +93,12 @@ func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interf
 
 	d.Set("active_profile", boot.ActiveProfile)
 	d.Set("architecture", boot.Architecture)
+	d.Set("authorized_keys", boot.AuthorizedKeys)
+	d.Set("host_key_fingerprints", boot.HostKeyFingerprints)
+	d.Set("hostname", boot.Hostname)
 	d.Set("ipv4_address", boot.ServerIPv4)
 	d.Set("ipv6_network", boot.ServerIPv6)
+	d.Set("keyboard", boot.Keyboard)
 	d.Set("language", boot.Language)
 	d.Set("operating_system", boot.OperatingSystem)
 	d.Set("password", boot.Password)
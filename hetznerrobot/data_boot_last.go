@@ -0,0 +1,89 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func dataBootLast() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBootLastRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server ID",
+			},
+			"profile": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{robot.BootProfileRescue, robot.BootProfileLinux}, false),
+				Description:  "Boot profile (`rescue` or `linux`)",
+			},
+			// read-only / computed
+			"architecture": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Architecture",
+			},
+			"authorized_keys": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Fingerprints of the authorized SSH keys",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"host_key_fingerprints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "MD5 fingerprints of the SSH host keys. Robot reports no key data, so they can't go into `known_hosts`: compare them with the fingerprint `ssh -o FingerprintHash=md5` shows on the first connection",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"keyboard": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Keyboard layout of the rescue system",
+			},
+			"language": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Language of the Linux installation",
+			},
+			"operating_system": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operating System / Distribution",
+			},
+		},
+	}
+}
+
+// dataSourceBootLastRead reads the configuration the profile was last activated with, e.g. to activate the same
+// rescue system again with hetzner-robot_boot.
+func dataSourceBootLastRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(HetznerRobotClient)
+
+	serverID := d.Get("server_id").(int)
+	profile := d.Get("profile").(string)
+	boot, err := c.getLastBoot(ctx, serverID, profile)
+	if err != nil {
+		return diag.Errorf("Unable to find the last %s configuration of server ID %d:\n\t %q", profile, serverID, err)
+	}
+
+	d.Set("architecture", boot.Architecture)
+	d.Set("authorized_keys", boot.AuthorizedKeys)
+	d.Set("host_key_fingerprints", boot.HostKeyFingerprints)
+	d.Set("keyboard", boot.Keyboard)
+	d.Set("language", boot.Language)
+	d.Set("operating_system", boot.OperatingSystem)
+	d.SetId(fmt.Sprintf("%d/%s", serverID, profile))
+
+	return nil
+}
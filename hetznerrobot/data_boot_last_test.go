@@ -0,0 +1,102 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccDataBootLast(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.Client()
+	ctx := context.Background()
+	rescue, err := client.Boot.ActivateRescue(ctx, testAccServerNumber, robot.RescueRequest{OS: "vkvm", Keyboard: "ch", AuthorizedKeys: []string{key.Fingerprint}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Boot.Deactivate(ctx, testAccServerNumber, robot.BootProfileRescue); err != nil {
+		t.Fatal(err)
+	}
+
+	config := func(profile string) string {
+		return testAccProviderConfig(fake) + fmt.Sprintf(`
+data "hetzner-robot_boot_last" "test" {
+  server_id = %d
+  profile   = %q
+}
+`, testAccServerNumber, profile)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("rescue"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_last.test", "id", "321/rescue"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_last.test", "operating_system", "vkvm"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_last.test", "keyboard", "ch"),
+					resource.TestCheckTypeSetElemAttr("data.hetzner-robot_boot_last.test", "authorized_keys.*", key.Fingerprint),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_last.test", "host_key_fingerprints.0", rescue.HostKeys[0].Fingerprint),
+				),
+			},
+			{
+				Config:      config("linux"),
+				ExpectError: regexp.MustCompile("Unable to find the last linux configuration"),
+			},
+		},
+	})
+}
+
+func TestGetLastBoot(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := HetznerRobotClient{Client: fake.Client()}
+	ctx := context.Background()
+
+	rescue := BootProfile{ActiveProfile: robot.BootProfileRescue, OperatingSystem: "vkvm", Architecture: "64", Keyboard: "ch", AuthorizedKeys: []string{key.Fingerprint}}
+	if _, err := c.setBootProfile(ctx, testAccServerNumber, rescue); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.unsetBootProfile(ctx, testAccServerNumber, robot.BootProfileRescue); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := c.getLastBoot(ctx, testAccServerNumber, robot.BootProfileRescue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.OperatingSystem != "vkvm" || last.Architecture != "64" || last.Keyboard != "ch" || len(last.AuthorizedKeys) != 1 || last.AuthorizedKeys[0] != key.Fingerprint {
+		t.Fatalf("unexpected last rescue configuration: %+v", last)
+	}
+
+	// the last configuration re-arms the same rescue system
+	if _, err := c.setBootProfile(ctx, testAccServerNumber, *last); err != nil {
+		t.Fatal(err)
+	}
+	boot, err := c.getBoot(ctx, testAccServerNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if boot.ActiveProfile != robot.BootProfileRescue || boot.OperatingSystem != "vkvm" || boot.Keyboard != "ch" || len(boot.AuthorizedKeys) != 1 {
+		t.Fatalf("unexpected re-armed rescue system: %+v", boot)
+	}
+
+	if _, err := c.getLastBoot(ctx, testAccServerNumber, robot.BootProfileLinux); !robot.IsNotFound(err) {
+		t.Fatalf("expected no last linux configuration, got %v", err)
+	}
+	if _, err := c.getLastBoot(ctx, testAccServerNumber, robot.BootProfileVNC); err == nil {
+		t.Fatal("expected vnc to have no last configuration")
+	}
+}
@@ -0,0 +1,64 @@
+package hetznerrobot
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"strconv"
+)
+
+func dataBootOptions() *schema.Resource {
+	optionList := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: description,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceBootOptionsRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server ID",
+			},
+			// read-only / computed
+			"rescue_operating_systems": optionList("Operating systems of the rescue system, empty while it is active"),
+			"rescue_architectures":     optionList("Architectures of the rescue system, empty while it is active"),
+			"rescue_keyboards":         optionList("Keyboard layouts of the rescue system, empty while it is active"),
+			"linux_operating_systems":  optionList("Distributions of the Linux installation, empty while it is active"),
+			"linux_architectures":      optionList("Architectures of the Linux installation, empty while it is active"),
+			"linux_languages":          optionList("Languages of the Linux installation, empty while it is active"),
+		},
+	}
+}
+
+func dataSourceBootOptionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(HetznerRobotClient)
+
+	serverID := d.Get("server_id").(int)
+	boot, err := c.Boot.Get(ctx, serverID)
+	if err != nil {
+		return diag.Errorf("Unable to find boot options for server ID %d:\n\t %q", serverID, err)
+	}
+
+	options := bootProfileOptions(boot)
+	rescue := options[robot.BootProfileRescue]
+	linux := options[robot.BootProfileLinux]
+
+	d.Set("rescue_operating_systems", rescue.OperatingSystems)
+	d.Set("rescue_architectures", rescue.Architectures)
+	d.Set("rescue_keyboards", rescue.Keyboards)
+	d.Set("linux_operating_systems", linux.OperatingSystems)
+	d.Set("linux_architectures", linux.Architectures)
+	d.Set("linux_languages", linux.Languages)
+	d.SetId(strconv.Itoa(serverID))
+
+	return nil
+}
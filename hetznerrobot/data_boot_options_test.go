@@ -0,0 +1,58 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccDataBootOptions(t *testing.T) {
+	fake := testAccFake(t)
+	if _, err := fake.Client().Boot.ActivateRescue(context.Background(), testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(fake) + fmt.Sprintf(`
+data "hetzner-robot_boot_options" "test" {
+  server_id = %d
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_options.test", "id", "321"),
+					// the options of the active rescue system aren't listed
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_options.test", "rescue_operating_systems.#", "0"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_options.test", "linux_operating_systems.#", "3"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_options.test", "linux_operating_systems.0", "Debian 12 base"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_options.test", "linux_architectures.0", "64"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot_options.test", "linux_languages.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataBootOptionsRead(t *testing.T) {
+	fake := testAccFake(t)
+	if _, err := fake.Client().Boot.ActivateLinux(context.Background(), testAccServerNumber, robot.LinuxRequest{Dist: "Debian 12 base", Lang: "en"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := testReadDataSource(t, fake, "hetzner-robot_boot_options", map[string]interface{}{"server_id": testAccServerNumber})
+	if keyboards := d.Get("rescue_keyboards").([]interface{}); len(keyboards) != 5 || keyboards[1] != "de" {
+		t.Fatalf("unexpected rescue keyboards: %v", keyboards)
+	}
+	if architectures := d.Get("rescue_architectures").([]interface{}); len(architectures) != 2 || architectures[0] != "64" {
+		t.Fatalf("unexpected rescue architectures: %v", architectures)
+	}
+	// the options of the active Linux installation aren't listed
+	if systems := d.Get("linux_operating_systems").([]interface{}); len(systems) != 0 {
+		t.Fatalf("expected no options of the active linux profile, got %v", systems)
+	}
+}
@@ -0,0 +1,53 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccDataBoot(t *testing.T) {
+	fake := testAccFake(t)
+	rescue, err := fake.Client().Boot.ActivateRescue(context.Background(), testAccServerNumber, robot.RescueRequest{OS: "linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(fake) + fmt.Sprintf(`
+data "hetzner-robot_boot" "test" {
+  server_id = %d
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "id", "321"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "active_profile", "rescue"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "operating_system", "linux"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "ipv4_address", testAccServerIP),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "password", rescue.Password),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "host_key_fingerprints.#", "1"),
+					testAccCheckBootHostKeyFingerprints(fake, "data.hetzner-robot_boot.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataBootRead(t *testing.T) {
+	fake := testAccFake(t)
+	if _, err := fake.Client().Boot.ActivateRescue(context.Background(), testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the server is selected by server_id, which the data source lacked
+	d := testReadDataSource(t, fake, "hetzner-robot_boot", map[string]interface{}{"server_id": testAccServerNumber})
+	if d.Id() != "321" || d.Get("active_profile") != "rescue" || d.Get("ipv4_address") != testAccServerIP {
+		t.Fatalf("unexpected boot configuration: %s %v %v", d.Id(), d.Get("active_profile"), d.Get("ipv4_address"))
+	}
+}
@@ -138,21 +138,38 @@ func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta inte
 
 	serverNumber := d.Get("server_number").(int)
 
-	server, err := c.getServer(ctx, serverNumber)
+	server, err := c.Servers.Get(ctx, serverNumber)
 	if err != nil {
-		return diag.Errorf("Unable to find Server with IP %s:\n\t %q", serverNumber, err)
+		return diag.Errorf("Unable to find Server with number %d:\n\t %q", serverNumber, err)
 	}
+	subnets := make([]map[string]interface{}, 0, len(server.Subnets))
+	for _, subnet := range server.Subnets {
+		subnets = append(subnets, map[string]interface{}{
+			"ip":   subnet.IP,
+			"mask": subnet.Mask,
+		})
+	}
+
 	d.Set("datacenter", server.DataCenter)
 	d.Set("is_cancelled", server.Cancelled)
 	d.Set("paid_until", server.PaidUntil)
 	d.Set("product", server.Product)
-	d.Set("server_ip_addresses", server.IPs)
+	d.Set("ip_addresses", server.IPs)
 	d.Set("server_ip", server.ServerIP)
-	d.Set("server_ip_v6_net", server.ServerIPv6)
+	d.Set("server_ipv6", server.ServerIPv6)
 	d.Set("server_name", server.ServerName)
-	d.Set("server_subnets", server.Subnets)
+	d.Set("server_subnets", subnets)
 	d.Set("status", server.Status)
 	d.Set("traffic", server.Traffic)
+	d.Set("linked_storagebox", server.LinkedStoragebox)
+	d.Set("reset", server.Reset)
+	d.Set("rescue", server.Rescue)
+	d.Set("vnc", server.VNC)
+	d.Set("windows", server.Windows)
+	d.Set("plesk", server.Plesk)
+	d.Set("cpanel", server.CPanel)
+	d.Set("wol", server.Wol)
+	d.Set("hot_swap", server.HotSwap)
 	d.SetId(strconv.Itoa(server.ServerNumber))
 
 	// Warning or errors can be collected in a slice type
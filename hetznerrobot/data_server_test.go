@@ -0,0 +1,51 @@
+package hetznerrobot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataServer(t *testing.T) {
+	fake := testAccFake(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(fake) + fmt.Sprintf(`
+data "hetzner-robot_server" "test" {
+  server_number = %d
+}
+`, testAccServerNumber),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "id", "321"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "server_name", "acc-test"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "server_ip", testAccServerIP),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "server_ipv6", "2a01:4f8:111:4221::"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "datacenter", "FSN1-DC14"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "product", "AX41"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "ip_addresses.#", "1"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "ip_addresses.0", testAccServerIP),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "rescue", "true"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "plesk", "false"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "wol", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataServerRead(t *testing.T) {
+	fake := testAccFake(t)
+
+	// the attributes were set under names missing from the schema
+	d := testReadDataSource(t, fake, "hetzner-robot_server", map[string]interface{}{"server_number": testAccServerNumber})
+	if d.Id() != "321" || d.Get("server_ipv6") != "2a01:4f8:111:4221::" || d.Get("ip_addresses.#") != 1 || d.Get("ip_addresses.0") != testAccServerIP {
+		t.Fatalf("unexpected server: %s %v %v", d.Id(), d.Get("server_ipv6"), d.Get("ip_addresses"))
+	}
+	if d.Get("rescue") != true || d.Get("wol") != true || d.Get("plesk") != false {
+		t.Fatalf("unexpected availability flags: %v %v %v", d.Get("rescue"), d.Get("wol"), d.Get("plesk"))
+	}
+}
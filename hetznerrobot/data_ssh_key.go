@@ -48,9 +48,9 @@ func dataSshKey() *schema.Resource {
 func dataSourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(HetznerRobotClient)
 
-	keyFingerprint := d.Id()
+	keyFingerprint := d.Get("fingerprint").(string)
 
-	key, err := c.getSshKey(ctx, keyFingerprint)
+	key, err := c.Keys.Get(ctx, keyFingerprint)
 	if err != nil {
 		return diag.Errorf("Unable to find SSH key %q:\n\t %q", keyFingerprint, err)
 	}
@@ -61,6 +61,7 @@ func dataSourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta inte
 	d.Set("type", key.Type)
 	d.Set("size", key.Size)
 	d.Set("created_at", key.CreatedAt)
+	d.SetId(key.Fingerprint)
 
 	return diag.Diagnostics{}
 }
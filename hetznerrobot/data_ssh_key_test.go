@@ -0,0 +1,50 @@
+package hetznerrobot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSshKey(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(fake) + fmt.Sprintf(`
+data "hetzner-robot_ssh_key" "test" {
+  fingerprint = %q
+}
+`, key.Fingerprint),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_ssh_key.test", "id", key.Fingerprint),
+					resource.TestCheckResourceAttr("data.hetzner-robot_ssh_key.test", "name", "deploy"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_ssh_key.test", "data", testAccKey),
+					resource.TestCheckResourceAttr("data.hetzner-robot_ssh_key.test", "type", "ED25519"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_ssh_key.test", "size", "256"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSshKeyRead(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the key was read by the ID, empty before the first read, instead of the fingerprint
+	d := testReadDataSource(t, fake, "hetzner-robot_ssh_key", map[string]interface{}{"fingerprint": key.Fingerprint})
+	if d.Id() != key.Fingerprint || d.Get("name") != "deploy" || d.Get("data") != testAccKey {
+		t.Fatalf("unexpected SSH key: %s %v %v", d.Id(), d.Get("name"), d.Get("data"))
+	}
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
 )
 
 func dataVSwitch() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceVSwitchRead,
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "vSwitch ID",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -103,19 +109,22 @@ func dataVSwitch() *schema.Resource {
 func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(HetznerRobotClient)
 
-	vSwitchID := d.Id()
-	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	vSwitchID, err := strconv.Atoi(d.Get("id").(string))
+	if err != nil {
+		return diag.Errorf("invalid vSwitch ID %q: %s", d.Get("id").(string), err)
+	}
+	vSwitch, err := c.VSwitch.Get(ctx, vSwitchID)
 	if err != nil {
 		return diag.Errorf("Unable to find VSwitch with ID %d:\n\t %q", vSwitchID, err)
 	}
 
 	d.Set("name", vSwitch.Name)
-	d.Set("vlan", vSwitch.Vlan)
+	d.Set("vlan", vSwitch.VLAN)
 	d.Set("is_cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
-	d.SetId(vSwitchID)
+	d.Set("servers", flattenVSwitchServers(vSwitch.Servers))
+	d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnets))
+	d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetworks))
+	d.SetId(strconv.Itoa(vSwitchID))
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
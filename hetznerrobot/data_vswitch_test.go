@@ -0,0 +1,57 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccDataVSwitch(t *testing.T) {
+	fake := testAccFake(t)
+	client := fake.Client()
+	vSwitch, err := client.VSwitch.Create(context.Background(), robot.VSwitchRequest{Name: "private", VLAN: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VSwitch.AddServers(context.Background(), vSwitch.ID, []int{testAccServerNumber}); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(fake) + fmt.Sprintf(`
+data "hetzner-robot_vswitch" "test" {
+  id = "%d"
+}
+`, vSwitch.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "name", "private"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "vlan", "4000"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "is_cancelled", "false"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "servers.#", "1"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "servers.0.server_number", "321"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataVSwitchRead(t *testing.T) {
+	fake := testAccFake(t)
+	vSwitch, err := fake.Client().VSwitch.Create(context.Background(), robot.VSwitchRequest{Name: "private", VLAN: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the vSwitch is selected by id, which the data source lacked
+	d := testReadDataSource(t, fake, "hetzner-robot_vswitch", map[string]interface{}{"id": strconv.Itoa(vSwitch.ID)})
+	if d.Id() != strconv.Itoa(vSwitch.ID) || d.Get("name") != "private" || d.Get("vlan") != 4000 {
+		t.Fatalf("unexpected vSwitch: %s %v %v", d.Id(), d.Get("name"), d.Get("vlan"))
+	}
+}
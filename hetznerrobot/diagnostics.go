@@ -0,0 +1,159 @@
+package hetznerrobot
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// paramPaths maps a webservice request parameter to the attribute it was built from, if any.
+type paramPaths func(param string) (cty.Path, bool)
+
+// paramAttributes maps parameters to top-level attributes of the same or another name.
+func paramAttributes(attributes map[string]string) paramPaths {
+	return func(param string) (cty.Path, bool) {
+		attribute, ok := attributes[param]
+		if !ok {
+			return nil, false
+		}
+		return cty.GetAttrPath(attribute), true
+	}
+}
+
+var firewallRuleParam = regexp.MustCompile(`^rules\[input\]\[(\d+)\]\[(\w+)\]$`)
+
+// firewallParamPaths maps e.g. rules[input][3][dst_port] to rule.3.dst_port. The ip_version of the rules
+// isn't configurable and stays unmapped.
+func firewallParamPaths(param string) (cty.Path, bool) {
+	if match := firewallRuleParam.FindStringSubmatch(param); match != nil {
+		if match[2] == "ip_version" {
+			return nil, false
+		}
+		index, _ := strconv.Atoi(match[1])
+		return cty.GetAttrPath("rule").IndexInt(index).GetAttr(match[2]), true
+	}
+	return paramAttributes(map[string]string{
+		"status":        "active",
+		"whitelist_hos": "whitelist_hos",
+	})(param)
+}
+
+var vSwitchParamPaths = paramAttributes(map[string]string{
+	"name":   "name",
+	"vlan":   "vlan",
+	"server": "servers",
+})
+
+var sshKeyParamPaths = paramAttributes(map[string]string{
+	"name": "name",
+	"data": "data",
+})
+
+var bootParamPaths = paramAttributes(map[string]string{
+	"os":             "operating_system",
+	"dist":           "operating_system",
+	"arch":           "architecture",
+	"lang":           "language",
+	"hostname":       "hostname",
+	"keyboard":       "keyboard",
+	"authorized_key": "authorized_keys",
+})
+
+// errorDiagnostics reports a failed request. The invalid and missing parameters of an INVALID_INPUT error get
+// a diagnostic each on the attribute they were built from, so that Terraform points at the offending
+// configuration; the error itself is only reported if some parameters have no attribute.
+func errorDiagnostics(summary string, err error, paths paramPaths) diag.Diagnostics {
+	var robotErr *robot.Error
+	if !errors.As(err, &robotErr) || robotErr.Code != "INVALID_INPUT" {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   err.Error(),
+		}}
+	}
+
+	var diags diag.Diagnostics
+	unmapped := false
+	add := func(params []string, attributeSummary string, problem string) {
+		for _, param := range params {
+			path, ok := paths(param)
+			if !ok {
+				unmapped = true
+				continue
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       attributeSummary,
+				Detail:        fmt.Sprintf("%s: Robot reported the request parameter %q as %s.", summary, param, problem),
+				AttributePath: path,
+			})
+		}
+	}
+	add(robotErr.Invalid, "Invalid value", "invalid")
+	add(robotErr.Missing, "Missing value", "missing")
+
+	if unmapped || len(diags) == 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   err.Error(),
+		})
+	}
+	return diags
+}
+
+// frameworkDiagnostics converts the diagnostics of the helpers shared with the SDKv2 resources for the resources
+// ported to the framework.
+func frameworkDiagnostics(diags diag.Diagnostics) fwdiag.Diagnostics {
+	var converted fwdiag.Diagnostics
+	for _, d := range diags {
+		attributePath, ok := frameworkPath(d.AttributePath)
+		switch {
+		case d.Severity == diag.Warning && ok:
+			converted.AddAttributeWarning(attributePath, d.Summary, d.Detail)
+		case d.Severity == diag.Warning:
+			converted.AddWarning(d.Summary, d.Detail)
+		case ok:
+			converted.AddAttributeError(attributePath, d.Summary, d.Detail)
+		default:
+			converted.AddError(d.Summary, d.Detail)
+		}
+	}
+	return converted
+}
+
+// frameworkPath converts an attribute path of the SDKv2 to the framework. Lists are the only indexed
+// attributes of the provider.
+func frameworkPath(p cty.Path) (path.Path, bool) {
+	if len(p) == 0 {
+		return path.Empty(), false
+	}
+
+	var converted path.Path
+	for i, step := range p {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			if i == 0 {
+				converted = path.Root(step.Name)
+			} else {
+				converted = converted.AtName(step.Name)
+			}
+		case cty.IndexStep:
+			if i == 0 || step.Key.Type() != cty.Number {
+				return path.Empty(), false
+			}
+			index, _ := step.Key.AsBigFloat().Int64()
+			converted = converted.AtListIndex(int(index))
+		default:
+			return path.Empty(), false
+		}
+	}
+	return converted, true
+}
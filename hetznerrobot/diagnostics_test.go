@@ -0,0 +1,87 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestErrorDiagnostics(t *testing.T) {
+	err := &robot.Error{
+		Status:  http.StatusBadRequest,
+		Code:    "INVALID_INPUT",
+		Message: "Invalid input parameters",
+		Invalid: []string{"rules[input][3][dst_port]", "rules[input][3][ip_version]"},
+		Missing: []string{"status"},
+	}
+
+	diags := errorDiagnostics("Unable to update firewall", err, firewallParamPaths)
+	if len(diags) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %v", diags)
+	}
+	if want := cty.GetAttrPath("rule").IndexInt(3).GetAttr("dst_port"); !diags[0].AttributePath.Equals(want) || diags[0].Summary != "Invalid value" {
+		t.Fatalf("unexpected diagnostic for dst_port: %+v", diags[0])
+	}
+	if want := cty.GetAttrPath("active"); !diags[1].AttributePath.Equals(want) || diags[1].Summary != "Missing value" {
+		t.Fatalf("unexpected diagnostic for status: %+v", diags[1])
+	}
+	// ip_version has no attribute, so the error is reported as a whole as well
+	if diags[2].AttributePath != nil || diags[2].Summary != "Unable to update firewall" {
+		t.Fatalf("unexpected diagnostic for the unmapped parameter: %+v", diags[2])
+	}
+
+	diags = errorDiagnostics("Unable to create VSwitch", &robot.Error{Status: http.StatusBadRequest, Code: "INVALID_INPUT", Invalid: []string{"vlan"}}, vSwitchParamPaths)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("vlan")) {
+		t.Fatalf("expected one diagnostic on vlan, got %v", diags)
+	}
+
+	diags = errorDiagnostics("Unable to create VSwitch", errors.New("connection reset"), vSwitchParamPaths)
+	if len(diags) != 1 || diags[0].AttributePath != nil || diags[0].Detail != "connection reset" {
+		t.Fatalf("expected the error as is, got %v", diags)
+	}
+}
+
+func TestErrorDiagnosticsFromRobot(t *testing.T) {
+	fake := testAccFake(t)
+	c := fake.Client()
+	ctx := context.Background()
+
+	_, err := c.Boot.ActivateLinux(ctx, testAccServerNumber, robot.LinuxRequest{Dist: "Windows 95"})
+	diags := errorDiagnostics("Unable to activate linux", err, bootParamPaths)
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %v", diags)
+	}
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("operating_system")) || diags[0].Summary != "Invalid value" {
+		t.Fatalf("unexpected diagnostic for dist: %+v", diags[0])
+	}
+	if !diags[1].AttributePath.Equals(cty.GetAttrPath("language")) || diags[1].Summary != "Missing value" {
+		t.Fatalf("unexpected diagnostic for lang: %+v", diags[1])
+	}
+
+	_, err = c.Keys.Create(ctx, robot.KeyCreateRequest{Name: "invalid", Data: "not a key"})
+	converted := frameworkDiagnostics(errorDiagnostics("Unable to create SSH key", err, sshKeyParamPaths))
+	if len(converted) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", converted)
+	}
+	if withPath, ok := converted[0].(interface{ Path() path.Path }); !ok || !withPath.Path().Equal(path.Root("data")) {
+		t.Fatalf("expected a diagnostic on data, got %+v", converted[0])
+	}
+}
+
+func TestFrameworkPath(t *testing.T) {
+	converted, ok := frameworkPath(cty.GetAttrPath("rule").IndexInt(3).GetAttr("dst_port"))
+	if !ok || !converted.Equal(path.Root("rule").AtListIndex(3).AtName("dst_port")) {
+		t.Fatalf("unexpected path: %s", converted)
+	}
+	if _, ok := frameworkPath(nil); ok {
+		t.Fatal("expected the empty path not to convert")
+	}
+	if _, ok := frameworkPath(cty.Path{cty.IndexStep{Key: cty.StringVal("key")}}); ok {
+		t.Fatal("expected a path without attribute not to convert")
+	}
+}
@@ -0,0 +1,97 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// permissionProbes are cheap reads of each endpoint family. %d stands for the number of a server of the account,
+// for the families without a list endpoint.
+var permissionProbes = map[string]string{
+	"boot":       "/boot/%d",
+	"failover":   "/failover",
+	"firewall":   "/firewall/%d",
+	"ip":         "/ip",
+	"key":        "/key",
+	"rdns":       "/rdns",
+	"reset":      "/reset/%d",
+	"server":     "/server",
+	"storagebox": "/storagebox",
+	"vswitch":    "/vswitch",
+}
+
+func permissionFamilies() []string {
+	families := make([]string, 0, len(permissionProbes))
+	for family := range permissionProbes {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	return families
+}
+
+// checkCredentials lists the servers of the account, so that rejected credentials fail the provider
+// configuration instead of the first resource using them.
+func checkCredentials(ctx context.Context, c *robot.Client, username string) ([]robot.Server, diag.Diagnostics) {
+	servers, err := c.Servers.List(ctx)
+	if err == nil {
+		return servers, nil
+	}
+
+	if robot.IsUnauthorized(err) {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Invalid Hetzner Robot credentials",
+			Detail: fmt.Sprintf("The webservice rejected the credentials of user %q. Check the username and password, "+
+				"and that the webservice user is enabled in Robot (Settings > Webservice and app settings).", username),
+		}}
+	}
+	return nil, diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Unable to validate Hetzner Robot credentials",
+		Detail:   err.Error(),
+	}}
+}
+
+// checkPermissions warns about the endpoint families the webservice user may not access. Families probed with a
+// server number are skipped for accounts without servers.
+func checkPermissions(ctx context.Context, c *robot.Client, families []string, servers []robot.Server) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, family := range families {
+		path := permissionProbes[family]
+		if strings.Contains(path, "%d") {
+			if len(servers) == 0 {
+				tflog.Debug(ctx, "no server to check the webservice permission with", map[string]interface{}{
+					"endpoint": family,
+				})
+				continue
+			}
+			path = fmt.Sprintf(path, servers[0].ServerNumber)
+		}
+
+		err := c.Do(ctx, http.MethodGet, path, nil, nil)
+		switch {
+		case err == nil, robot.IsNotFound(err):
+		case robot.IsUnauthorized(err), robot.IsForbidden(err):
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("No webservice access to /%s", family),
+				Detail: fmt.Sprintf("Robot refused GET %s, so resources and data sources using the /%s endpoints will fail. "+
+					"Grant the webservice user access to them in Robot.", path, family),
+			})
+		default:
+			tflog.Debug(ctx, "unable to check the webservice permission", map[string]interface{}{
+				"endpoint": family,
+				"error":    err.Error(),
+			})
+		}
+	}
+	return diags
+}
@@ -2,12 +2,18 @@ package hetznerrobot
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
 // Provider -
-func Provider() *schema.Provider {
+func Provider(version string) *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"username": {
@@ -20,34 +26,256 @@ func Provider() *schema.Provider {
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PASSWORD", nil),
 			},
+			"password_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PASSWORD_FILE", nil),
+				Description: "File containing the webservice password, used when `password` is not set",
+			},
+			"credential_process": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Command (and arguments) printing `{\"username\": \"...\", \"password\": \"...\"}` on stdout, used for the credentials not set otherwise",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PROFILE", nil),
+				Description: "Named profile of the config file to take the credentials from",
+			},
+			"config_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_CONFIG_FILE", nil),
+				Description: "Config file holding the profiles. Defaults to `~/.config/hetzner-robot/config.toml`",
+			},
 			"url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_URL", "https://robot-ws.your-server.de"),
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_URL", robot.DefaultBaseURL),
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HETZNERROBOT_MAX_RETRIES", robot.DefaultMaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of retries for rate-limited or transiently failing requests",
+			},
+			"max_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HETZNERROBOT_MAX_BACKOFF", robot.DefaultMaxBackoff.String()),
+				ValidateDiagFunc: validateDuration,
+				Description:      "Maximum wait between two retries, as a duration (e.g. `30s`, `2m`). Rate-limited requests wait for the interval Robot reports if it is within this maximum and the timeout of the operation, and otherwise fail right away with the time the limit resets",
+			},
+			"timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HETZNERROBOT_TIMEOUT", robot.DefaultTimeout.String()),
+				ValidateDiagFunc: validateDuration,
+				Description:      "Timeout of a single webservice request, as a duration (e.g. `60s`)",
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_PROXY_URL", nil),
+				Description: "Proxy used to reach the webservice. Defaults to the `HTTPS_PROXY` / `NO_PROXY` environment",
+			},
+			"ca_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_CA_FILE", nil),
+				Description: "Path to a PEM bundle of additional trusted certificate authorities",
+			},
+			"tls_min_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1.2",
+				ValidateFunc: validation.StringInSlice([]string{"1.2", "1.3"}, false),
+				Description:  "Minimum TLS version (`1.2` or `1.3`)",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HETZNERROBOT_MAX_CONCURRENT_REQUESTS", robot.DefaultMaxConcurrentRequests),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of webservice requests in flight across all resources",
+			},
+			"request_budgets": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Requests per hour allowed per endpoint family (e.g. `boot`, `reset`, `firewall`), overriding the built-in budgets. `0` disables the budget of a family",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"read_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_READ_CACHE", true),
+				Description: "Answer reads of SSH keys, and reads of servers and vSwitches that no longer exist, from one list request per endpoint and run. Enabled by default",
+			},
+			"log_metadata_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_LOG_METADATA_ONLY", false),
+				Description: "Only log method, URI, status and size of webservice requests, never parameters or response bodies",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_READ_ONLY", false),
+				Description: "Refuse every webservice request that could change something (POST, PUT, DELETE), e.g. for drift detection and audit pipelines",
+			},
+			"audit_log": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_AUDIT_LOG", nil),
+				Description: "File to append a JSON line to for every mutating webservice request, with the type and ID of its resource, the ID assigned by creates, redacted parameters, status and error code",
+			},
+			"skip_credentials_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_SKIP_CREDENTIALS_VALIDATION", false),
+				Description: "Don't check the credentials with a webservice request when the provider is configured",
+			},
+			"check_permissions": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Endpoint families (e.g. `boot`, `firewall`, `storagebox`) to check webservice access to when the provider is configured, warning about the ones the webservice user lacks",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(permissionFamilies(), false),
+				},
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Extra HTTP headers sent with every request",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 		},
-		ResourcesMap: map[string]*schema.Resource{
+		ResourcesMap: withCallers(map[string]*schema.Resource{
 			"hetzner-robot_boot":     resourceBoot(),
 			"hetzner-robot_firewall": resourceFirewall(),
 			"hetzner-robot_vswitch":  resourceVSwitch(),
-			"hetzner-robot_ssh_key":  resourceSshKey(),
-		},
-		DataSourcesMap: map[string]*schema.Resource{
-			"hetzner-robot_boot":    dataBoot(),
-			"hetzner-robot_server":  dataServer(),
-			"hetzner-robot_vswitch": dataVSwitch(),
-			"hetzner-robot_ssh_key": dataSshKey(),
-		},
-		ConfigureContextFunc: providerConfigure,
+		}),
+		DataSourcesMap: withCallers(map[string]*schema.Resource{
+			"hetzner-robot_boot":         dataBoot(),
+			"hetzner-robot_boot_last":    dataBootLast(),
+			"hetzner-robot_boot_options": dataBootOptions(),
+			"hetzner-robot_server":       dataServer(),
+			"hetzner-robot_vswitch":      dataVSwitch(),
+			"hetzner-robot_ssh_key":      dataSshKey(),
+		}),
+		ConfigureContextFunc: providerConfigure(version),
 	}
 }
 
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	username := d.Get("username").(string)
-	password := d.Get("password").(string)
-	url := d.Get("url").(string)
+func providerConfigure(version string) schema.ConfigureContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		credentialProcess := make([]string, 0)
+		for _, arg := range d.Get("credential_process").([]interface{}) {
+			credentialProcess = append(credentialProcess, arg.(string))
+		}
 
-	var diags diag.Diagnostics
+		username, password, err := resolveCredentials(ctx, credentialSources{
+			Username:          d.Get("username").(string),
+			Password:          d.Get("password").(string),
+			PasswordFile:      d.Get("password_file").(string),
+			CredentialProcess: credentialProcess,
+			Profile:           d.Get("profile").(string),
+			ConfigFile:        d.Get("config_file").(string),
+		})
+		if err != nil {
+			return nil, diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Unable to resolve Hetzner Robot credentials",
+				Detail:   err.Error(),
+			}}
+		}
 
-	return NewHetznerRobotClient(username, password, url), diags
+		url := d.Get("url").(string)
+		maxRetries := d.Get("max_retries").(int)
+		maxBackoff, _ := time.ParseDuration(d.Get("max_backoff").(string))
+		timeout, _ := time.ParseDuration(d.Get("timeout").(string))
+
+		budgets := make(map[string]int)
+		for family, budget := range d.Get("request_budgets").(map[string]interface{}) {
+			budgets[family] = budget.(int)
+		}
+
+		headers := make(map[string]string)
+		for name, value := range d.Get("headers").(map[string]interface{}) {
+			headers[name] = value.(string)
+		}
+
+		httpClient, err := robot.NewHTTPClient(robot.TransportConfig{
+			Timeout:       timeout,
+			ProxyURL:      d.Get("proxy_url").(string),
+			CAFile:        d.Get("ca_file").(string),
+			TLSMinVersion: d.Get("tls_min_version").(string),
+		})
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		var diags diag.Diagnostics
+
+		var journal *robot.Journal
+		if path := d.Get("audit_log").(string); path != "" {
+			if path, err = expandHome(path); err == nil {
+				journal, err = robot.OpenJournal(path)
+			}
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+		}
+
+		client := robot.NewClient(username, password,
+			robot.WithBaseURL(url),
+			robot.WithRetries(maxRetries, maxBackoff),
+			robot.WithHTTPClient(httpClient),
+			robot.WithUserAgent(fmt.Sprintf("terraform-provider-hetzner-robot/%s", version)),
+			robot.WithHeaders(headers),
+			robot.WithScheduler(d.Get("max_concurrent_requests").(int), budgets),
+			robot.WithReadCache(d.Get("read_cache").(bool)),
+			robot.WithLogMetadataOnly(d.Get("log_metadata_only").(bool)),
+			robot.WithReadOnly(d.Get("read_only").(bool)),
+			robot.WithJournal(journal),
+		)
+
+		if !d.Get("skip_credentials_validation").(bool) {
+			servers, probeDiags := checkCredentials(ctx, client, username)
+			diags = append(diags, probeDiags...)
+			if probeDiags.HasError() {
+				return nil, diags
+			}
+
+			families := make([]string, 0)
+			for _, family := range d.Get("check_permissions").([]interface{}) {
+				families = append(families, family.(string))
+			}
+			diags = append(diags, checkPermissions(ctx, client, families, servers)...)
+		}
+
+		return HetznerRobotClient{Client: client}, diags
+	}
+}
+
+func validateDuration(v interface{}, path cty.Path) diag.Diagnostics {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	return nil
 }
@@ -0,0 +1,119 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// configuredClient hands the client configured by the SDKv2 provider to the framework provider. The SDKv2
+// provider is served first, so it owns the provider configuration until it is ported as well.
+type configuredClient struct {
+	client *HetznerRobotClient
+}
+
+// frameworkProvider serves the resources already ported to terraform-plugin-framework.
+type frameworkProvider struct {
+	version    string
+	schema     map[string]*schema.Schema
+	configured *configuredClient
+}
+
+var _ provider.Provider = &frameworkProvider{}
+
+func newFrameworkProvider(version string, sdkSchema map[string]*schema.Schema, configured *configuredClient) provider.Provider {
+	return &frameworkProvider{
+		version:    version,
+		schema:     sdkSchema,
+		configured: configured,
+	}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "hetzner-robot"
+	resp.Version = p.version
+}
+
+// Schema mirrors the schema of the SDKv2 provider, which the muxed providers have to agree on. Validation is left
+// to the SDKv2 provider, so that invalid values aren't reported twice.
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	attributes := make(map[string]providerschema.Attribute, len(p.schema))
+	for name, s := range p.schema {
+		attribute, err := frameworkProviderAttribute(s)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to convert the provider schema", fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		attributes[name] = attribute
+	}
+	resp.Schema = providerschema.Schema{Attributes: attributes}
+}
+
+func frameworkProviderAttribute(s *schema.Schema) (providerschema.Attribute, error) {
+	switch s.Type {
+	case schema.TypeString:
+		return providerschema.StringAttribute{Optional: s.Optional, Required: s.Required, Description: s.Description}, nil
+	case schema.TypeInt:
+		return providerschema.Int64Attribute{Optional: s.Optional, Required: s.Required, Description: s.Description}, nil
+	case schema.TypeBool:
+		return providerschema.BoolAttribute{Optional: s.Optional, Required: s.Required, Description: s.Description}, nil
+	case schema.TypeList:
+		elementType, err := frameworkElementType(s.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return providerschema.ListAttribute{ElementType: elementType, Optional: s.Optional, Required: s.Required, Description: s.Description}, nil
+	case schema.TypeMap:
+		elementType, err := frameworkElementType(s.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return providerschema.MapAttribute{ElementType: elementType, Optional: s.Optional, Required: s.Required, Description: s.Description}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", s.Type)
+}
+
+func frameworkElementType(elem interface{}) (attr.Type, error) {
+	s, ok := elem.(*schema.Schema)
+	if !ok {
+		return nil, fmt.Errorf("unsupported element %T", elem)
+	}
+	switch s.Type {
+	case schema.TypeString:
+		return types.StringType, nil
+	case schema.TypeInt:
+		return types.Int64Type, nil
+	case schema.TypeBool:
+		return types.BoolType, nil
+	}
+	return nil, fmt.Errorf("unsupported element type %s", s.Type)
+}
+
+// Configure takes over the client of the SDKv2 provider. The mux doesn't configure the framework provider if the
+// configuration of the SDKv2 provider failed.
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	if p.configured.client == nil {
+		resp.Diagnostics.AddError("Unconfigured Hetzner Robot client",
+			"The provider was configured before its client. Please report this issue to the provider developers.")
+		return
+	}
+	resp.ResourceData = *p.configured.client
+	resp.DataSourceData = *p.configured.client
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newSshKeyResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
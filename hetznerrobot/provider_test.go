@@ -0,0 +1,166 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+// Acceptance tests run against an in-memory fake of the webservice (robottest) and need a terraform binary:
+//
+//	TF_ACC=1 go test ./hetznerrobot/...
+
+const (
+	testAccServerNumber = 321
+	testAccServerIP     = "123.123.123.123"
+
+	// testAccPanelServerNumber is a second server, offering the Plesk and cPanel installations.
+	testAccPanelServerNumber = 322
+
+	// testAccLocalServerNumber is a server on the loopback address, where the tests can answer on SSH.
+	testAccLocalServerNumber = 323
+
+	testAccKey      = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHZ4mA0FFDiw6HTBz9ah1qYmyuyRlYB4FeIZeaCZZ1g3 test@example"
+	testAccOtherKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKug+uI4ahKZNkrb7H06L56Xfm61OnTuMxbT+s/DOP4y other@example"
+)
+
+var testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"hetzner-robot": func() (tfprotov5.ProviderServer, error) {
+		server, err := ProviderServer(context.Background(), "test")
+		if err != nil {
+			return nil, err
+		}
+		return server(), nil
+	},
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider("test").InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestProviderServer checks that the SDKv2 and the framework provider agree on the provider schema and don't
+// both serve a resource, which the mux reports as diagnostics.
+func TestProviderServer(t *testing.T) {
+	server, err := ProviderServer(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+	if _, ok := resp.ResourceSchemas["hetzner-robot_ssh_key"]; !ok {
+		t.Error("expected the framework provider to serve hetzner-robot_ssh_key")
+	}
+}
+
+func TestAccProviderInvalidCredentials(t *testing.T) {
+	fake := testAccFake(t)
+	config := testAccProviderConfig(fake)
+	fake.Password = "changed-in-robot"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config + fmt.Sprintf(`
+data "hetzner-robot_server" "test" {
+  server_number = %d
+}
+`, testAccServerNumber),
+				ExpectError: regexp.MustCompile("Invalid Hetzner Robot credentials"),
+			},
+		},
+	})
+}
+
+func TestCheckPermissions(t *testing.T) {
+	fake := testAccFake(t)
+	fake.InjectFault(robottest.Fault{Method: http.MethodGet, Path: "/firewall", Status: http.StatusForbidden, Code: "FORBIDDEN", Message: "Forbidden"})
+	client := fake.Client()
+	ctx := context.Background()
+
+	servers, diags := checkCredentials(ctx, client, fake.Username)
+	if diags.HasError() || len(servers) != 1 {
+		t.Fatalf("expected the credentials to be accepted, got %v, %v", servers, diags)
+	}
+
+	diags = checkPermissions(ctx, client, []string{"boot", "firewall", "storagebox", "vswitch"}, servers)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Summary != "No webservice access to /firewall" {
+		t.Fatalf("expected a warning about /firewall, got %v", diags)
+	}
+}
+
+// testAccFake starts a fake webservice with one server offering every boot profile.
+func testAccFake(t *testing.T) *robottest.Server {
+	t.Helper()
+
+	fake := robottest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddServer(robot.Server{
+		ServerNumber: testAccServerNumber,
+		ServerIP:     testAccServerIP,
+		ServerIPv6:   "2a01:4f8:111:4221::",
+		ServerName:   "acc-test",
+		Product:      "AX41",
+		DataCenter:   "FSN1-DC14",
+		Traffic:      "unlimited",
+		PaidUntil:    "2030-12-31",
+		Rescue:       true,
+		VNC:          true,
+		Windows:      true,
+		Wol:          true,
+	})
+	return fake
+}
+
+// testAccProviderConfig configures the provider for fake, without request budgets slowing the tests down.
+func testAccProviderConfig(fake *robottest.Server) string {
+	return fmt.Sprintf(`
+provider "hetzner-robot" {
+  username    = %q
+  password    = %q
+  url         = %q
+  max_backoff = "10ms"
+
+  request_budgets = {
+    boot     = 0
+    firewall = 0
+    key      = 0
+    reset    = 0
+    server   = 0
+    vswitch  = 0
+  }
+}
+`, fake.Username, fake.Password, fake.URL)
+}
+
+// testReadDataSource reads the data source name of the provider with config against fake, without terraform.
+func testReadDataSource(t *testing.T, fake *robottest.Server, name string, config map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	dataSource, ok := Provider("test").DataSourcesMap[name]
+	if !ok {
+		t.Fatalf("data source %s not found", name)
+	}
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, config)
+	if diags := dataSource.ReadContext(context.Background(), d, HetznerRobotClient{Client: fake.Client()}); diags.HasError() {
+		t.Fatalf("unable to read %s: %v", name, diags)
+	}
+	return d
+}
@@ -0,0 +1,105 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// recoveryTimeout bounds the lookup after an ambiguous create, which also runs when the create timeout
+// has already expired.
+const recoveryTimeout = time.Minute
+
+// recoveryContext keeps the values of ctx, e.g. the caller and the log masks, but not its deadline.
+func recoveryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), recoveryTimeout)
+}
+
+// recoverVSwitch looks for the vSwitch an ambiguously failed create request may have created nonetheless.
+// Robot refuses a second active vSwitch with the same VLAN, so an active one matching name and VLAN is ours,
+// unless it is one of the vSwitches listed before the create. Without one, the create error is returned.
+func recoverVSwitch(ctx context.Context, c *robot.Client, request robot.VSwitchRequest, existing []robot.VSwitch, createErr error) (*robot.VSwitch, error) {
+	if !robot.IsAmbiguous(createErr) {
+		return nil, createErr
+	}
+
+	ctx, cancel := recoveryContext(ctx)
+	defer cancel()
+
+	vSwitches, err := c.VSwitch.List(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "unable to look for a vSwitch created by a failed request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, createErr
+	}
+
+	existingIDs := make(map[int]bool, len(existing))
+	for _, vSwitch := range existing {
+		existingIDs[vSwitch.ID] = true
+	}
+	for _, vSwitch := range vSwitches {
+		if !vSwitch.Cancelled && vSwitch.Name == request.Name && vSwitch.VLAN == request.VLAN && !existingIDs[vSwitch.ID] {
+			tflog.Warn(ctx, "adopting vSwitch created by a failed request", map[string]interface{}{
+				"id":    vSwitch.ID,
+				"error": createErr.Error(),
+			})
+			return &vSwitch, nil
+		}
+	}
+	return nil, createErr
+}
+
+// recoverSshKey looks up the key an ambiguously failed create request may have added nonetheless, by the
+// fingerprint of its data. The key is only adopted if it has the requested name or was created after the
+// request started at started, a key with the same data added before is reported as KEY_ALREADY_EXISTS.
+// Without one, the create error is returned.
+func recoverSshKey(ctx context.Context, c *robot.Client, request robot.KeyCreateRequest, started time.Time, createErr error) (*robot.Key, error) {
+	if !robot.IsAmbiguous(createErr) {
+		return nil, createErr
+	}
+
+	fingerprint, err := robot.KeyFingerprint(request.Data)
+	if err != nil {
+		return nil, createErr
+	}
+
+	ctx, cancel := recoveryContext(ctx)
+	defer cancel()
+
+	key, err := c.Keys.Get(ctx, fingerprint)
+	if err != nil {
+		if !robot.IsNotFound(err) {
+			tflog.Warn(ctx, "unable to look for an SSH key created by a failed request", map[string]interface{}{
+				"fingerprint": fingerprint,
+				"error":       err.Error(),
+			})
+		}
+		return nil, createErr
+	}
+
+	if key.Name != request.Name && !keyCreatedAfter(key, started) {
+		return nil, &robot.Error{
+			Status:  http.StatusConflict,
+			Code:    "KEY_ALREADY_EXISTS",
+			Message: fmt.Sprintf("the key already exists with the name %q, created at %s before the failed request", key.Name, key.CreatedAt),
+		}
+	}
+
+	tflog.Warn(ctx, "adopting SSH key created by a failed request", map[string]interface{}{
+		"fingerprint": fingerprint,
+		"error":       createErr.Error(),
+	})
+	return key, nil
+}
+
+// keyCreatedAfter reports whether key was created after t. Robot reports created_at to the second and
+// without a zone, it is taken as UTC.
+func keyCreatedAfter(key *robot.Key, t time.Time) bool {
+	createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", key.CreatedAt, time.UTC)
+	return err == nil && createdAt.After(t)
+}
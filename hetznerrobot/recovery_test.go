@@ -0,0 +1,87 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+func TestRecoverSshKey(t *testing.T) {
+	fake := testAccFake(t)
+	c := fake.Client()
+	ctx := context.Background()
+
+	// errors that rule out a create are returned as they are
+	invalid := &robot.Error{Status: http.StatusBadRequest, Code: "INVALID_INPUT"}
+	if _, err := recoverSshKey(ctx, c, robot.KeyCreateRequest{Name: "deploy", Data: testAccKey}, time.Now(), invalid); err != invalid {
+		t.Fatalf("expected the create error, got %v", err)
+	}
+
+	// a lost response of a create that went through
+	request := robot.KeyCreateRequest{Name: "deploy", Data: testAccKey}
+	fake.InjectFault(robottest.LostResponse(http.MethodPost, "/key", 1))
+	started := time.Now()
+	_, createErr := c.Keys.Create(ctx, request)
+	if !robot.IsAmbiguous(createErr) {
+		t.Fatalf("expected an ambiguous error, got %v", createErr)
+	}
+	key, err := recoverSshKey(ctx, c, request, started, createErr)
+	if err != nil || key.Name != "deploy" {
+		t.Fatalf("expected the key to be adopted by its name, got %+v, %v", key, err)
+	}
+
+	// a key with the same data, added under another name before the request
+	other := robot.KeyCreateRequest{Name: "other", Data: testAccKey}
+	started = time.Now()
+	fake.InjectFault(robottest.InternalError(http.MethodPost, "/key", "INTERNAL_ERROR", 1))
+	_, createErr = c.Keys.Create(ctx, other)
+	if _, err := recoverSshKey(ctx, c, other, started, createErr); !robot.HasErrorCode(err, "KEY_ALREADY_EXISTS") {
+		t.Fatalf("expected KEY_ALREADY_EXISTS, got %v", err)
+	}
+
+	// ... or under another name after the request started
+	if key, err := recoverSshKey(ctx, c, other, started.Add(-time.Minute), createErr); err != nil || key.Name != "deploy" {
+		t.Fatalf("expected the key created after the request started to be adopted, got %+v, %v", key, err)
+	}
+
+	// no key at all
+	missing := robot.KeyCreateRequest{Name: "missing", Data: testAccOtherKey}
+	if _, err := recoverSshKey(ctx, c, missing, started, createErr); err != createErr {
+		t.Fatalf("expected the create error, got %v", err)
+	}
+}
+
+func TestRecoverVSwitch(t *testing.T) {
+	fake := testAccFake(t)
+	c := fake.Client()
+	ctx := context.Background()
+
+	request := robot.VSwitchRequest{Name: "test", VLAN: 4000}
+	if _, err := c.VSwitch.Create(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	existing, err := c.VSwitch.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a vSwitch matching name and VLAN that existed before the create
+	fake.InjectFault(robottest.InternalError(http.MethodPost, "/vswitch", "INTERNAL_ERROR", 1))
+	_, createErr := c.VSwitch.Create(ctx, request)
+	if vSwitch, err := recoverVSwitch(ctx, c, request, existing, createErr); err != createErr {
+		t.Fatalf("expected the existing vSwitch not to be adopted, got %+v, %v", vSwitch, err)
+	}
+
+	// a lost response of a create that went through
+	request = robot.VSwitchRequest{Name: "lost", VLAN: 4001}
+	fake.InjectFault(robottest.LostResponse(http.MethodPost, "/vswitch", 1))
+	_, createErr = c.VSwitch.Create(ctx, request)
+	vSwitch, err := recoverVSwitch(ctx, c, request, existing, createErr)
+	if err != nil || vSwitch.Name != "lost" || vSwitch.VLAN != 4001 {
+		t.Fatalf("expected the created vSwitch to be adopted, got %+v, %v", vSwitch, err)
+	}
+}
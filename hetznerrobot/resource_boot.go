@@ -2,9 +2,21 @@ package hetznerrobot
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func resourceBoot() *schema.Resource {
@@ -17,6 +29,21 @@ func resourceBoot() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceBootImportState,
 		},
+		CustomizeDiff: resourceBootCustomizeDiff,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceBootV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceBootStateUpgradeV0,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"server_id": {
@@ -26,39 +53,91 @@ func resourceBoot() *schema.Resource {
 			},
 			// optional
 			"active_profile": {
-				Type:        schema.TypeString, // Enum should be better (linux/rescue/...)
-				Optional:    true,
-				Description: "Active boot profile",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(bootProfiles, false),
+				Description:  "Active boot profile (`rescue`, `linux`, `vnc`, `windows`, `plesk` or `cpanel`)",
 			},
 			"architecture": {
-				Type:        schema.TypeString, // Enum should be better (amd64/...)
+				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Active Architecture",
+				Computed:    true,
+				Description: "Active Architecture (all profiles but `windows`), see `hetzner-robot_boot_options`",
 			},
 			"language": {
-				Type:        schema.TypeString, // Enum should be better (amd64/...)
+				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Language",
+				Computed:    true,
+				Description: "Language (`linux`, `vnc`, `windows`, `plesk` and `cpanel`), see `hetzner-robot_boot_options`",
 			},
 			"operating_system": {
-				Type:        schema.TypeString, // Enum should be better (ubuntu_20.04/...)
+				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Active Operating System / Distribution",
+				Computed:    true,
+				Description: "Active Operating System / Distribution, see `hetzner-robot_boot_options`",
 			},
 			"authorized_keys": {
-				Type:        schema.TypeList,
+				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "One or more SSH key fingerprints",
+				Description: "One or more SSH key fingerprints (`rescue` and `linux`)",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Hostname of the installation (`plesk` and `cpanel`, required there)",
+			},
+			"reset_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{robot.ResetSoftware, robot.ResetHardware, robot.ResetPower}, false),
+				Description:  "Reset the server after activating the profile, so that it boots into it (`sw`, `hw` or `power`). The profile stays in state once the boot has consumed it. Robot keeps the last configuration of `rescue` and `linux` only, so a `vnc`, `windows`, `plesk` or `cpanel` profile activated and consumed again outside of Terraform since goes unnoticed",
+			},
+			"wait_for_boot": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				RequiredWith: []string{"reset_type"},
+				Description:  "Wait until the server has booted into the profile after the reset, within the create / update timeout",
+			},
+			"wait_for_install": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				RequiredWith: []string{"reset_type"},
+				Description:  "Wait until the installation has finished after the reset, i.e. the profile is deactivated and the server answers on SSH with the host keys of the installation, within the create / update timeout (`linux`). An installation takes about 10 to 20 minutes, so raise these timeouts from their default of 5 minutes, e.g. to 30 minutes",
+			},
+			"keyboard": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Keyboard layout of the rescue system (`rescue`), see `hetzner-robot_boot_options`",
+			},
+			"resolution": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Screen resolution of the VNC installation. Not supported: Robot's VNC installation takes no resolution, so setting it fails the plan",
+			},
+			"keep_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Leave the active profile armed when the resource is destroyed, instead of deactivating it",
+			},
 			// read-only / computed
 			"ipv4_address": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Server main IPv4 address",
 			},
+			"host_key_fingerprints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "MD5 fingerprints of the SSH host keys of the rescue system / Linux installation. Robot reports no key data, so they can't go into `known_hosts`: compare them with the fingerprint `ssh -o FingerprintHash=md5` shows on the first connection",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"ipv6_network": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -67,7 +146,7 @@ func resourceBoot() *schema.Resource {
 			"password": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "Current Rescue System root password / Linux installation password or null",
+				Description: "Current Rescue System root password / installation password or null",
 				Sensitive:   true,
 			},
 		},
@@ -75,25 +154,10 @@ func resourceBoot() *schema.Resource {
 }
 
 func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
-
 	serverID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return nil, err
 	}
-
-	boot, err := c.getBoot(ctx, serverID)
-	if err != nil {
-		return nil, err
-	}
-
-	d.Set("active_profile", boot.ActiveProfile)
-	d.Set("architecture", boot.Architecture)
-	d.Set("ipv4_address", boot.ServerIPv4)
-	d.Set("ipv6_network", boot.ServerIPv6)
-	d.Set("language", boot.Language)
-	d.Set("operating_system", boot.OperatingSystem)
-	d.Set("password", boot.Password)
 	d.Set("server_id", serverID)
 
 	results := make([]*schema.ResourceData, 1)
@@ -101,24 +165,139 @@ func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta i
 	return results, nil
 }
 
-func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+// resourceBootCustomizeDiff rejects the arguments the configured profile doesn't take, e.g. a hostname for linux,
+// before anything is sent to Robot.
+func resourceBootCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// re-arming the profile generates a new password and new host keys
+	if d.Id() != "" && d.HasChanges("active_profile", "architecture", "authorized_keys", "hostname", "keyboard", "language", "operating_system") {
+		if err := d.SetNewComputed("password"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("host_key_fingerprints"); err != nil {
+			return err
+		}
+	}
+
+	profile := d.Get("active_profile").(string)
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return nil
+	}
+	if err := checkBootProfileArguments(profile, config); err != nil {
+		return err
+	}
+
+	if meta == nil || !d.NewValueKnown("server_id") || !d.HasChanges("active_profile", "architecture", "keyboard", "language", "operating_system") {
+		return nil
+	}
+	return resourceBootCheckOptions(ctx, d, meta.(HetznerRobotClient), profile)
+}
+
+// checkBootProfileArguments rejects the configured arguments profile doesn't take and requires the ones Robot has
+// no default for.
+func checkBootProfileArguments(profile string, config cty.Value) error {
+	if !config.GetAttr("resolution").IsNull() {
+		return fmt.Errorf("resolution is not supported: Robot's VNC installation takes no screen resolution, set it in the installed system instead")
+	}
+	if profile == "" {
+		return nil
+	}
+
+	for _, argument := range []string{"architecture", "authorized_keys", "hostname", "keyboard", "language"} {
+		if !config.GetAttr(argument).IsNull() && !slices.Contains(bootProfileArguments[profile], argument) {
+			return fmt.Errorf("%s is not supported by the %s boot profile", argument, profile)
+		}
+	}
+	if !config.GetAttr("wait_for_install").IsNull() && profile != robot.BootProfileLinux {
+		return fmt.Errorf("wait_for_install is not supported by the %s boot profile", profile)
+	}
+	for _, argument := range bootProfileRequiredArguments[profile] {
+		if config.GetAttr(argument).IsNull() {
+			return fmt.Errorf("%s is required by the %s boot profile", argument, profile)
+		}
+	}
+	return nil
+}
 
+// resourceBootCheckOptions checks the configured arguments against the values Robot lists for the profile, so that
+// e.g. a misspelled distribution fails the plan instead of the apply. The options of the active profile aren't
+// listed and can't be checked.
+func resourceBootCheckOptions(ctx context.Context, d *schema.ResourceDiff, c HetznerRobotClient, profile string) error {
 	serverID := d.Get("server_id").(int)
-	activeBootProfile := d.Get("active_profile").(string)
-	arch := d.Get("architecture").(string)
-	os := d.Get("operating_system").(string)
-	lang := d.Get("language").(string)
-	authorizedKeys := make([]string, 0)
-	if input := d.Get("authorized_keys"); input != nil {
-		for _, key := range input.([]interface{}) {
-			authorizedKeys = append(authorizedKeys, key.(string))
+	boot, err := c.Boot.Get(robot.ContextWithCaller(ctx, "hetzner-robot_boot "+strconv.Itoa(serverID)), serverID)
+	if err != nil {
+		tflog.Warn(ctx, "unable to check the boot options", map[string]interface{}{
+			"server_id": serverID,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return checkBootOptions(boot, serverID, profile, d.GetRawConfig())
+}
+
+// checkBootOptions rejects a profile the server doesn't offer and configured arguments outside the options Robot
+// lists for it.
+func checkBootOptions(boot *robot.BootConfig, serverID int, profile string, config cty.Value) error {
+	if !bootProfileAvailable(boot, profile) {
+		return fmt.Errorf("the %s boot profile is not available for server %d", profile, serverID)
+	}
+	options, ok := bootProfileOptions(boot)[profile]
+	if !ok {
+		return nil
+	}
+
+	for argument, values := range map[string][]string{
+		"architecture":     options.Architectures,
+		"keyboard":         options.Keyboards,
+		"language":         options.Languages,
+		"operating_system": options.OperatingSystems,
+	} {
+		value := config.GetAttr(argument)
+		if value.IsNull() || !value.IsKnown() || len(values) == 0 || slices.Contains(values, value.AsString()) {
+			continue
 		}
+		return fmt.Errorf("%s %q is not available for the %s boot profile of server %d, expected one of: %s",
+			argument, value.AsString(), profile, serverID, strings.Join(values, ", "))
+	}
+	return nil
+}
+
+// bootProfileFromResourceData returns the profile to activate. Computed arguments keep the value read back for the
+// previous profile, so only the ones the profile takes are passed on.
+func bootProfileFromResourceData(d *schema.ResourceData) BootProfile {
+	profile := BootProfile{
+		ActiveProfile:   d.Get("active_profile").(string),
+		OperatingSystem: d.Get("operating_system").(string),
+		AuthorizedKeys:  make([]string, 0),
+	}
+	arguments := bootProfileArguments[profile.ActiveProfile]
+	if slices.Contains(arguments, "architecture") {
+		profile.Architecture = d.Get("architecture").(string)
+	}
+	if slices.Contains(arguments, "hostname") {
+		profile.Hostname = d.Get("hostname").(string)
+	}
+	if slices.Contains(arguments, "keyboard") {
+		profile.Keyboard = d.Get("keyboard").(string)
+	}
+	if slices.Contains(arguments, "language") {
+		profile.Language = d.Get("language").(string)
 	}
+	for _, key := range d.Get("authorized_keys").(*schema.Set).List() {
+		profile.AuthorizedKeys = append(profile.AuthorizedKeys, key.(string))
+	}
+	return profile
+}
+
+func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(HetznerRobotClient)
+
+	serverID := d.Get("server_id").(int)
+	profile := bootProfileFromResourceData(d)
 
-	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, authorizedKeys)
+	bootProfile, err := c.setBootProfile(ctx, serverID, profile)
 	if err != nil {
-		return diag.FromErr(err)
+		return errorDiagnostics(fmt.Sprintf("Unable to activate boot profile %q of server %d", profile.ActiveProfile, serverID), err, bootParamPaths)
 	}
 
 	d.Set("ipv4_address", bootProfile.ServerIPv4)
@@ -126,10 +305,161 @@ func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interf
 	d.Set("password", bootProfile.Password)
 	d.SetId(strconv.Itoa(serverID))
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	// read while the profile is active, the boot consumes it
+	if diags := resourceBootRead(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	return resourceBootReset(ctx, d, c, d.Timeout(schema.TimeoutCreate))
+}
 
-	return diags
+// resourceBootReset resets the server into the profile just activated if reset_type is set, and with
+// wait_for_boot waits for the boot, with wait_for_install for the installation to finish as well.
+func resourceBootReset(ctx context.Context, d *schema.ResourceData, c HetznerRobotClient, timeout time.Duration) diag.Diagnostics {
+	serverID := d.Get("server_id").(int)
+	activeBootProfile := d.Get("active_profile").(string)
+	resetType := d.Get("reset_type").(string)
+	if resetType == "" {
+		return nil
+	}
+
+	if _, err := c.Reset.Execute(ctx, serverID, resetType); err != nil {
+		return diag.Errorf("Unable to reset server %d into boot profile %q:\n\t %q", serverID, activeBootProfile, err)
+	}
+
+	waitForInstallation := d.Get("wait_for_install").(bool)
+	if !d.Get("wait_for_boot").(bool) && !waitForInstallation {
+		return nil
+	}
+	deadline := time.Now().Add(timeout)
+	if err := waitForBoot(ctx, c, serverID, activeBootProfile, timeout); err != nil {
+		return diag.FromErr(err)
+	}
+	if waitForInstallation {
+		var hostKeyFingerprints []string
+		for _, fingerprint := range d.Get("host_key_fingerprints").([]interface{}) {
+			hostKeyFingerprints = append(hostKeyFingerprints, fingerprint.(string))
+		}
+		if err := waitForInstall(ctx, serverID, c.sshAddress(d.Get("ipv4_address").(string)), hostKeyFingerprints, time.Until(deadline)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// waitForBoot waits until the server has booted into profile, which Robot reports by deactivating the profile.
+func waitForBoot(ctx context.Context, c HetznerRobotClient, serverID int, profile string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"armed"},
+		Target:  []string{"booted"},
+		Refresh: func() (interface{}, string, error) {
+			boot, err := c.getBoot(ctx, serverID)
+			if err != nil {
+				return nil, "", err
+			}
+			if boot.ActiveProfile == profile {
+				tflog.Info(ctx, "waiting for the server to boot into the boot profile", map[string]interface{}{
+					"server_id": serverID,
+					"profile":   profile,
+				})
+				return boot, "armed", nil
+			}
+			return boot, "booted", nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for server %d to boot into the %s boot profile: %w", serverID, profile, err)
+	}
+	return nil
+}
+
+// waitForInstall waits for the installation the linux profile started to finish, i.e. for the installed system to
+// answer on the SSH address. The installation runs on the boot that deactivated the profile, and ends with the
+// reboot into the installed system. The installer may answer on SSH as well, so only the host keys Robot reported
+// for the installation tell that it has finished.
+func waitForInstall(ctx context.Context, serverID int, address string, hostKeyFingerprints []string, timeout time.Duration) error {
+	if len(hostKeyFingerprints) == 0 {
+		return fmt.Errorf("unable to wait for the linux installation on server %d: Robot reported no host keys to recognize it by", serverID)
+	}
+
+	start := time.Now()
+	tflog.Info(ctx, "waiting for the linux installation to finish", map[string]interface{}{
+		"server_id": serverID,
+		"address":   address,
+		"timeout":   timeout.String(),
+	})
+
+	var lastErr error
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"installing"},
+		Target:  []string{"installed"},
+		Refresh: func() (interface{}, string, error) {
+			fingerprint, err := probeSSHHostKey(ctx, address)
+			if err == nil && !slices.Contains(hostKeyFingerprints, fingerprint) {
+				err = fmt.Errorf("host key %s isn't one of the installation", fingerprint)
+			}
+			if err != nil {
+				lastErr = err
+				tflog.Info(ctx, "linux installation still in progress", map[string]interface{}{
+					"server_id": serverID,
+					"elapsed":   time.Since(start).Round(time.Second).String(),
+					"ssh_error": err.Error(),
+				})
+				return "", "installing", nil
+			}
+			return fingerprint, "installed", nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+	fingerprint, err := stateConf.WaitForStateContext(ctx)
+	var timeoutErr *retry.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return fmt.Errorf("timeout waiting for the linux installation on server %d to finish: %s doesn't answer on SSH with "+
+			"the host keys of the installation after %s (%v), check the installation on the KVM console or raise the create / update timeout",
+			serverID, address, time.Since(start).Round(time.Second), lastErr)
+	}
+	if err != nil {
+		return fmt.Errorf("error waiting for the linux installation on server %d to finish: %w", serverID, err)
+	}
+
+	tflog.Info(ctx, "linux installation finished", map[string]interface{}{
+		"server_id": serverID,
+		"elapsed":   time.Since(start).Round(time.Second).String(),
+		"host_key":  fingerprint,
+	})
+	return nil
+}
+
+// errHostKeyReceived aborts the SSH handshake of probeSSHHostKey once the server presented its host key.
+var errHostKeyReceived = errors.New("host key received")
+
+// probeSSHHostKey connects to address and returns the MD5 fingerprint of the host key the SSH server presents,
+// without authenticating.
+func probeSSHHostKey(ctx context.Context, address string) (string, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return "", err
+	}
+	var fingerprint string
+	_, _, _, err = ssh.NewClientConn(conn, address, &ssh.ClientConfig{
+		User: "root",
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintLegacyMD5(key)
+			return errHostKeyReceived
+		},
+	})
+	if fingerprint == "" {
+		return "", fmt.Errorf("no SSH host key received: %w", err)
+	}
+	return fingerprint, nil
 }
 
 func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -138,13 +468,35 @@ func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	serverID := d.Get("server_id").(int)
 	boot, err := c.getBoot(ctx, serverID)
 	if err != nil {
+		if robot.IsNotFound(err) {
+			tflog.Warn(ctx, "boot configuration not found, removing it from state", map[string]interface{}{
+				"server_id": serverID,
+			})
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(err)
 	}
 
+	// with reset_type the profile is meant to be consumed by the boot
+	if boot.ActiveProfile == "" && d.Get("reset_type").(string) != "" && d.Get("active_profile").(string) != "" {
+		consumed, err := resourceBootReadConsumed(ctx, d, c, boot)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if consumed {
+			return nil
+		}
+	}
+
 	d.Set("active_profile", boot.ActiveProfile)
 	d.Set("architecture", boot.Architecture)
+	d.Set("authorized_keys", boot.AuthorizedKeys)
+	d.Set("host_key_fingerprints", boot.HostKeyFingerprints)
+	d.Set("hostname", boot.Hostname)
 	d.Set("ipv4_address", boot.ServerIPv4)
 	d.Set("ipv6_network", boot.ServerIPv6)
+	d.Set("keyboard", boot.Keyboard)
 	d.Set("language", boot.Language)
 	d.Set("operating_system", boot.OperatingSystem)
 	d.Set("password", boot.Password)
@@ -155,39 +507,154 @@ func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	return diags
 }
 
+// resourceBootReadConsumed keeps the profile of a resource with reset_type in state once the boot consumed it, and
+// reports false if it was activated again outside of Terraform since. Robot then reports neither the profile nor
+// its password, so the rescue and linux profiles are refreshed from the configuration they were last activated
+// with, whose host keys tell the activations apart. The other profiles have no last configuration and are kept.
+func resourceBootReadConsumed(ctx context.Context, d *schema.ResourceData, c HetznerRobotClient, boot *BootProfile) (bool, error) {
+	serverID := d.Get("server_id").(int)
+	profile := d.Get("active_profile").(string)
+
+	if profile == robot.BootProfileRescue || profile == robot.BootProfileLinux {
+		last, err := c.getLastBoot(ctx, serverID, profile)
+		if robot.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		var hostKeyFingerprints []string
+		for _, fingerprint := range d.Get("host_key_fingerprints").([]interface{}) {
+			hostKeyFingerprints = append(hostKeyFingerprints, fingerprint.(string))
+		}
+		if !slices.Equal(hostKeyFingerprints, last.HostKeyFingerprints) {
+			tflog.Debug(ctx, "boot profile activated again since, refreshing it", map[string]interface{}{
+				"server_id": serverID,
+				"profile":   profile,
+			})
+			return false, nil
+		}
+
+		d.Set("architecture", last.Architecture)
+		d.Set("authorized_keys", last.AuthorizedKeys)
+		d.Set("keyboard", last.Keyboard)
+		d.Set("language", last.Language)
+		d.Set("operating_system", last.OperatingSystem)
+	}
+
+	tflog.Debug(ctx, "boot profile consumed by the reset, keeping it in state", map[string]interface{}{
+		"server_id": serverID,
+		"profile":   profile,
+	})
+	d.Set("ipv4_address", boot.ServerIPv4)
+	d.Set("ipv6_network", boot.ServerIPv6)
+	return true, nil
+}
+
 func resourceBootUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(HetznerRobotClient)
 
 	serverID := d.Get("server_id").(int)
-	activeBootProfile := d.Get("active_profile").(string)
-	arch := d.Get("architecture").(string)
-	os := d.Get("operating_system").(string)
-	lang := d.Get("language").(string)
-	authorizedKeys := make([]string, 0)
-	if input := d.Get("authorized_keys"); input != nil {
-		for _, key := range input.([]interface{}) {
-			authorizedKeys = append(authorizedKeys, key.(string))
+
+	// keep_on_destroy only matters when the resource is destroyed, reset_type, wait_for_boot and wait_for_install
+	// when a profile is activated
+	if !d.HasChangesExcept("keep_on_destroy", "reset_type", "wait_for_boot", "wait_for_install") {
+		return nil
+	}
+
+	// Robot refuses to activate a profile while another one is active
+	if oldProfile, _ := d.GetChange("active_profile"); oldProfile.(string) != "" {
+		if err := c.unsetBootProfile(ctx, serverID, oldProfile.(string)); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, authorizedKeys)
+	profile := bootProfileFromResourceData(d)
+
+	if profile.ActiveProfile == "" {
+		d.Set("password", "")
+		d.Set("host_key_fingerprints", []string{})
+		return nil
+	}
+
+	bootProfile, err := c.setBootProfile(ctx, serverID, profile)
 	if err != nil {
-		return diag.FromErr(err)
+		return errorDiagnostics(fmt.Sprintf("Unable to activate boot profile %q of server %d", profile.ActiveProfile, serverID), err, bootParamPaths)
 	}
 
 	d.Set("ipv4_address", bootProfile.ServerIPv4)
 	d.Set("ipv6_network", bootProfile.ServerIPv6)
 	d.Set("password", bootProfile.Password)
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
-
-	return diags
+	if diags := resourceBootRead(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	return resourceBootReset(ctx, d, c, d.Timeout(schema.TimeoutUpdate))
 }
 
+// resourceBootDelete deactivates the active profile, which would otherwise fire on the next reboot of the server.
 func resourceBootDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	c := meta.(HetznerRobotClient)
 
-	return diags
+	serverID := d.Get("server_id").(int)
+	activeBootProfile := d.Get("active_profile").(string)
+	if activeBootProfile == "" {
+		return nil
+	}
+
+	if d.Get("keep_on_destroy").(bool) {
+		tflog.Info(ctx, "keeping boot profile active", map[string]interface{}{
+			"server_id": serverID,
+			"profile":   activeBootProfile,
+		})
+		return nil
+	}
+
+	if err := c.unsetBootProfile(ctx, serverID, activeBootProfile); err != nil {
+		return diag.Errorf("Unable to deactivate boot profile %q of server %d:\n\t %q", activeBootProfile, serverID, err)
+	}
+
+	return nil
+}
+
+// resourceBootV0 is the schema of hetzner-robot_boot before authorized_keys became a set.
+func resourceBootV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"server_id":        {Type: schema.TypeInt, Required: true},
+			"active_profile":   {Type: schema.TypeString, Optional: true},
+			"architecture":     {Type: schema.TypeString, Optional: true},
+			"language":         {Type: schema.TypeString, Optional: true},
+			"operating_system": {Type: schema.TypeString, Optional: true},
+			"authorized_keys": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ipv4_address": {Type: schema.TypeString, Computed: true},
+			"ipv6_network": {Type: schema.TypeString, Computed: true},
+			"password":     {Type: schema.TypeString, Computed: true, Sensitive: true},
+		},
+	}
+}
+
+// resourceBootStateUpgradeV0 turns the authorized_keys list into a set. Both are stored as arrays, so only duplicate
+// fingerprints, which a set can't hold, are dropped.
+func resourceBootStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	keys, ok := rawState["authorized_keys"].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+	authorizedKeys := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if !slices.Contains(authorizedKeys, key) {
+			authorizedKeys = append(authorizedKeys, key)
+		}
+	}
+	rawState["authorized_keys"] = authorizedKeys
+	return rawState, nil
 }
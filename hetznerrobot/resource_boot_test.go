@@ -0,0 +1,915 @@
+package hetznerrobot
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	sdkterraform "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestAccBoot(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := fake.AddKey("other", testAccOtherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// in the reverse of the order Robot lists them in
+	fingerprints := []string{key.Fingerprint, otherKey.Fingerprint}
+	if fingerprints[0] < fingerprints[1] {
+		fingerprints[0], fingerprints[1] = fingerprints[1], fingerprints[0]
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckBootProfile(fake, testAccServerNumber, ""),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBootConfig(fake, fmt.Sprintf(`
+  active_profile   = "rescue"
+  operating_system = "linux"
+  keyboard         = "de"
+  authorized_keys  = [%q, %q]
+`, fingerprints[0], fingerprints[1])),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "id", "321"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "rescue"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "operating_system", "linux"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "keyboard", "de"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "ipv4_address", testAccServerIP),
+					resource.TestCheckResourceAttrSet("hetzner-robot_boot.test", "password"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "authorized_keys.#", "2"),
+					resource.TestCheckTypeSetElemAttr("hetzner-robot_boot.test", "authorized_keys.*", key.Fingerprint),
+					resource.TestCheckTypeSetElemAttr("hetzner-robot_boot.test", "authorized_keys.*", otherKey.Fingerprint),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "host_key_fingerprints.#", "1"),
+					testAccCheckBootHostKeyFingerprints(fake, "hetzner-robot_boot.test"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_boot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "linux"
+  operating_system = "Debian 12 base"
+  language         = "en"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "linux"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "operating_system", "Debian 12 base"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "language", "en"),
+					resource.TestCheckResourceAttrSet("hetzner-robot_boot.test", "password"),
+				),
+			},
+			{
+				// drift: the profile is deactivated outside of Terraform
+				PreConfig: func() {
+					if err := fake.Client().Boot.Deactivate(context.Background(), testAccServerNumber, robot.BootProfileLinux); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccBootConfig(fake, `
+  active_profile   = "linux"
+  operating_system = "Debian 12 base"
+  language         = "en"
+`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "linux"
+  operating_system = "Debian 12 base"
+  language         = "en"
+`),
+				Check: resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "linux"),
+			},
+		},
+	})
+}
+
+func TestAccBoot_keepOnDestroy(t *testing.T) {
+	fake := testAccFake(t)
+	var password string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckBootProfile(fake, testAccServerNumber, robot.BootProfileRescue),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+`),
+				Check: resource.TestCheckResourceAttrWith("hetzner-robot_boot.test", "password", func(value string) error {
+					password = value
+					return nil
+				}),
+			},
+			{
+				// keep_on_destroy alone doesn't re-arm the profile, which would change its password
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  keep_on_destroy  = true
+`),
+				Check: resource.TestCheckResourceAttrWith("hetzner-robot_boot.test", "password", func(value string) error {
+					if value != password {
+						return fmt.Errorf("expected the rescue system to stay armed with password %q, got %q", password, value)
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+func TestAccBoot_invalidOptions(t *testing.T) {
+	fake := testAccFake(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "linux"
+  operating_system = "Debian 99 base"
+  language         = "en"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`operating_system "Debian 99 base" is not available for the linux boot profile`),
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  architecture     = "128"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`architecture "128" is not available for the rescue boot profile`),
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  keyboard         = "dvorak"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`keyboard "dvorak" is not available for the rescue boot profile`),
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "linux"
+  operating_system = "Debian 12 base"
+  language         = "en"
+  keyboard         = "de"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("keyboard is not supported by the linux boot profile"),
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "plesk"
+  operating_system = "Debian 12 base"
+  hostname         = "plesk.example.com"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("the plesk boot profile is not available for server 321"),
+			},
+		},
+	})
+}
+
+func TestAccBoot_reset(t *testing.T) {
+	fake := testAccFake(t)
+	fake.SetProcessingReads(1)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckBootProfile(fake, testAccServerNumber, ""),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  wait_for_boot    = true
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"wait_for_boot": all of ` + "`reset_type,wait_for_boot`" + ` must be specified`),
+			},
+			{
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  reset_type       = "hw"
+  wait_for_boot    = true
+`),
+				Check: resource.ComposeTestCheckFunc(
+					// the server booted into the rescue system, which consumed the profile
+					testAccCheckBootProfile(fake, testAccServerNumber, ""),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "rescue"),
+					resource.TestCheckResourceAttrSet("hetzner-robot_boot.test", "password"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "host_key_fingerprints.#", "1"),
+				),
+			},
+			{
+				// changing the reset arguments alone neither re-arms nor resets
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  reset_type       = "sw"
+`),
+				Check: testAccCheckBootProfile(fake, testAccServerNumber, ""),
+			},
+			{
+				// a rescue system activated and deactivated outside of Terraform shows as a change
+				PreConfig: func() {
+					client := fake.Client()
+					if _, err := client.Boot.ActivateRescue(context.Background(), testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+						t.Fatal(err)
+					}
+					if err := client.Boot.Deactivate(context.Background(), testAccServerNumber, robot.BootProfileRescue); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccBootConfig(fake, `
+  active_profile   = "rescue"
+  operating_system = "linux"
+  reset_type       = "sw"
+`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestUnsetBootProfile(t *testing.T) {
+	fake := testAccFake(t)
+	c := HetznerRobotClient{Client: fake.Client()}
+	ctx := context.Background()
+	conflict := robottest.Fault{Method: http.MethodDelete, Path: "/boot", Status: http.StatusConflict, Code: "CONFLICT", Message: "Conflict", Times: 1}
+
+	if _, err := c.Boot.ActivateRescue(ctx, testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.unsetBootProfile(ctx, testAccServerNumber, robot.BootProfileRescue); err != nil {
+		t.Fatal(err)
+	}
+
+	// already inactive
+	fake.InjectFault(conflict)
+	if err := c.unsetBootProfile(ctx, testAccServerNumber, robot.BootProfileRescue); err != nil {
+		t.Fatalf("expected an inactive profile to count as deactivated, got %v", err)
+	}
+
+	if _, err := c.Boot.ActivateRescue(ctx, testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+	fake.InjectFault(conflict)
+	if err := c.unsetBootProfile(ctx, testAccServerNumber, robot.BootProfileRescue); !robot.HasErrorCode(err, "CONFLICT") {
+		t.Fatalf("expected the error of the still active profile, got %v", err)
+	}
+}
+
+func TestResourceBootDelete(t *testing.T) {
+	fake := testAccFake(t)
+	c := HetznerRobotClient{Client: fake.Client()}
+	ctx := context.Background()
+
+	destroy := func(keep bool) {
+		t.Helper()
+		d := schema.TestResourceDataRaw(t, resourceBoot().Schema, map[string]interface{}{
+			"server_id":       testAccServerNumber,
+			"active_profile":  robot.BootProfileRescue,
+			"keep_on_destroy": keep,
+		})
+		if diags := resourceBootDelete(ctx, d, c); diags.HasError() {
+			t.Fatalf("unable to destroy: %v", diags)
+		}
+	}
+	activeProfile := func() string {
+		t.Helper()
+		boot, err := c.getBoot(ctx, testAccServerNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return boot.ActiveProfile
+	}
+
+	if _, err := c.Boot.ActivateRescue(ctx, testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+	destroy(true)
+	if profile := activeProfile(); profile != robot.BootProfileRescue {
+		t.Fatalf("expected keep_on_destroy to leave rescue active, got %q", profile)
+	}
+
+	destroy(false)
+	if profile := activeProfile(); profile != "" {
+		t.Fatalf("expected the profile to be deactivated, got %q", profile)
+	}
+
+	// already inactive
+	destroy(false)
+}
+
+func TestResourceBootStateUpgradeV0(t *testing.T) {
+	ctx := context.Background()
+	first, second := "11:22:33:44:55:66:77:88:99:00:aa:bb:cc:dd:ee:ff", "56:29:99:a4:5d:ed:ac:95:c1:f5:88:82:90:5d:dd:10"
+	rawState := fmt.Sprintf(`{"id":"%[1]d","server_id":%[1]d,"active_profile":"rescue","architecture":"64","authorized_keys":[%[2]q,%[3]q,%[2]q],"ipv4_address":"123.123.123.123","password":"secret"}`,
+		testAccServerNumber, first, second)
+
+	// the upgrade terraform runs on a state of the list schema
+	resp, err := schema.NewGRPCProviderServer(Provider("test")).UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "hetzner-robot_boot",
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: []byte(rawState)},
+	})
+	if err != nil || len(resp.Diagnostics) > 0 {
+		t.Fatalf("unable to upgrade the state: %v %v", err, resp.Diagnostics)
+	}
+	upgraded, err := msgpack.Unmarshal(resp.UpgradedState.MsgPack, resourceBoot().CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := upgraded.GetAttr("authorized_keys"); !keys.Type().IsSetType() || keys.LengthInt() != 2 {
+		t.Fatalf("expected a set of 2 keys, got %#v", keys)
+	}
+
+	// ... which plans no change, whatever the order of the keys in the configuration
+	state, err := resourceBoot().ShimInstanceStateFromValue(upgraded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := resourceBoot().Diff(ctx, state, sdkterraform.NewResourceConfigRaw(map[string]interface{}{
+		"server_id":       testAccServerNumber,
+		"active_profile":  robot.BootProfileRescue,
+		"architecture":    "64",
+		"authorized_keys": []interface{}{second, first},
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		return
+	}
+	// host_key_fingerprints, which the old schema lacks, is computed until the refresh before the plan reads it
+	for name := range diff.Attributes {
+		if !strings.HasPrefix(name, "host_key_fingerprints.") {
+			t.Fatalf("expected no change of %s, got %+v", name, diff.Attributes[name])
+		}
+	}
+	if diff.RequiresNew() {
+		t.Fatal("expected no replacement")
+	}
+}
+
+func TestResourceBootReset(t *testing.T) {
+	fake := testAccFake(t)
+	c := HetznerRobotClient{Client: fake.Client()}
+	ctx := context.Background()
+	rescue := BootProfile{ActiveProfile: robot.BootProfileRescue, OperatingSystem: "linux"}
+	resourceData := func(waitForBoot bool) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, resourceBoot().Schema, map[string]interface{}{
+			"server_id":        testAccServerNumber,
+			"active_profile":   robot.BootProfileRescue,
+			"operating_system": "linux",
+			"reset_type":       robot.ResetHardware,
+			"wait_for_boot":    waitForBoot,
+		})
+	}
+	countRequests := func(method string, path string) int {
+		count := 0
+		for _, request := range fake.Requests() {
+			if request.Method == method && request.Path == path {
+				count++
+			}
+		}
+		return count
+	}
+	resets, bootReads := fmt.Sprintf("/reset/%d", testAccServerNumber), fmt.Sprintf("/boot/%d", testAccServerNumber)
+
+	// without wait_for_boot the reset is sent, nothing more
+	fake.SetProcessingReads(1)
+	if _, err := c.setBootProfile(ctx, testAccServerNumber, rescue); err != nil {
+		t.Fatal(err)
+	}
+	reads := countRequests(http.MethodGet, bootReads)
+	if diags := resourceBootReset(ctx, resourceData(false), c, time.Minute); diags.HasError() {
+		t.Fatalf("unable to reset: %v", diags)
+	}
+	if count := countRequests(http.MethodPost, resets); count != 1 {
+		t.Fatalf("expected 1 reset, got %d", count)
+	}
+	if count := countRequests(http.MethodGet, bootReads); count != reads {
+		t.Fatalf("expected no boot configuration to be polled, got %d reads", count-reads)
+	}
+	if err := c.unsetBootProfile(ctx, testAccServerNumber, robot.BootProfileRescue); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait_for_boot polls until the boot deactivated the profile, the first read still reports it active
+	if _, err := c.setBootProfile(ctx, testAccServerNumber, rescue); err != nil {
+		t.Fatal(err)
+	}
+	reads = countRequests(http.MethodGet, bootReads)
+	if diags := resourceBootReset(ctx, resourceData(true), c, time.Minute); diags.HasError() {
+		t.Fatalf("unable to reset: %v", diags)
+	}
+	if count := countRequests(http.MethodGet, bootReads) - reads; count != 2 {
+		t.Fatalf("expected 2 reads of the boot configuration, got %d", count)
+	}
+	if boot, err := c.getBoot(ctx, testAccServerNumber); err != nil || boot.ActiveProfile != "" {
+		t.Fatalf("expected the boot to consume the profile, got %+v, %v", boot, err)
+	}
+
+	// a server that doesn't boot
+	fake.SetProcessingReads(100)
+	if _, err := c.setBootProfile(ctx, testAccServerNumber, rescue); err != nil {
+		t.Fatal(err)
+	}
+	diags := resourceBootReset(ctx, resourceData(true), c, time.Second)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "error waiting for server 321 to boot into the rescue boot profile") {
+		t.Fatalf("expected a timeout, got %v", diags)
+	}
+
+	fake.InjectFault(robottest.InternalError(http.MethodPost, "/reset", "INTERNAL_ERROR", robot.DefaultMaxRetries+1))
+	diags = resourceBootReset(ctx, resourceData(true), c, time.Minute)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "Unable to reset server 321 into boot profile \"rescue\"") {
+		t.Fatalf("expected the reset to fail, got %v", diags)
+	}
+}
+
+// testBootConfig returns a configuration of hetzner-robot_boot with the given arguments, the others are null.
+func testBootConfig(arguments map[string]cty.Value) cty.Value {
+	attributes := make(map[string]cty.Value)
+	for name, attributeType := range resourceBoot().CoreConfigSchema().ImpliedType().AttributeTypes() {
+		attributes[name] = cty.NullVal(attributeType)
+	}
+	for name, value := range arguments {
+		attributes[name] = value
+	}
+	return cty.ObjectVal(attributes)
+}
+
+func TestCheckBootProfileArguments(t *testing.T) {
+	keys := cty.SetVal([]cty.Value{cty.StringVal("fingerprint")})
+	cases := []struct {
+		profile   string
+		arguments map[string]cty.Value
+		err       string
+	}{
+		{"", nil, ""},
+		{"", map[string]cty.Value{"resolution": cty.StringVal("1024x768")}, "resolution is not supported"},
+		{"rescue", map[string]cty.Value{"architecture": cty.StringVal("64"), "authorized_keys": keys, "keyboard": cty.StringVal("de")}, ""},
+		{"rescue", map[string]cty.Value{"language": cty.StringVal("en")}, "language is not supported by the rescue boot profile"},
+		{"linux", map[string]cty.Value{"authorized_keys": keys, "language": cty.StringVal("en"), "wait_for_install": cty.True}, ""},
+		{"linux", map[string]cty.Value{"keyboard": cty.StringVal("de")}, "keyboard is not supported by the linux boot profile"},
+		{"vnc", map[string]cty.Value{"architecture": cty.StringVal("64"), "language": cty.StringVal("en_US")}, ""},
+		{"vnc", map[string]cty.Value{"resolution": cty.StringVal("1024x768")}, "resolution is not supported: Robot's VNC installation takes no screen resolution"},
+		{"vnc", map[string]cty.Value{"authorized_keys": keys}, "authorized_keys is not supported by the vnc boot profile"},
+		{"vnc", map[string]cty.Value{"wait_for_install": cty.True}, "wait_for_install is not supported by the vnc boot profile"},
+		{"windows", map[string]cty.Value{"language": cty.StringVal("de")}, ""},
+		{"windows", map[string]cty.Value{"architecture": cty.StringVal("64")}, "architecture is not supported by the windows boot profile"},
+		{"plesk", map[string]cty.Value{"hostname": cty.StringVal("plesk.example.com"), "architecture": cty.StringVal("64")}, ""},
+		{"plesk", nil, "hostname is required by the plesk boot profile"},
+		{"cpanel", map[string]cty.Value{"hostname": cty.StringVal("cpanel.example.com"), "keyboard": cty.StringVal("de")}, "keyboard is not supported by the cpanel boot profile"},
+		{"cpanel", nil, "hostname is required by the cpanel boot profile"},
+	}
+	for _, c := range cases {
+		err := checkBootProfileArguments(c.profile, testBootConfig(c.arguments))
+		if c.err == "" && err != nil || c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s %v: expected %q, got %v", c.profile, c.arguments, c.err, err)
+		}
+	}
+}
+
+func TestCheckBootOptions(t *testing.T) {
+	fake := testAccFake(t)
+	c := fake.Client()
+	ctx := context.Background()
+
+	boot, err := c.Boot.Get(ctx, testAccServerNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		profile   string
+		arguments map[string]cty.Value
+		err       string
+	}{
+		{"rescue", map[string]cty.Value{"operating_system": cty.StringVal("linux"), "architecture": cty.StringVal("64"), "keyboard": cty.StringVal("de")}, ""},
+		{"rescue", map[string]cty.Value{"keyboard": cty.StringVal("dvorak")}, `keyboard "dvorak" is not available for the rescue boot profile of server 321, expected one of: us, de, fr, ch, uk`},
+		{"linux", map[string]cty.Value{"operating_system": cty.StringVal("Debian 12 base"), "language": cty.StringVal("de")}, ""},
+		{"linux", map[string]cty.Value{"operating_system": cty.StringVal("Windows 95")}, `operating_system "Windows 95" is not available for the linux boot profile`},
+		{"linux", map[string]cty.Value{"architecture": cty.StringVal("32")}, `architecture "32" is not available for the linux boot profile`},
+		// unknown values are checked at apply
+		{"linux", map[string]cty.Value{"operating_system": cty.UnknownVal(cty.String)}, ""},
+		{"vnc", map[string]cty.Value{"language": cty.StringVal("en")}, `language "en" is not available for the vnc boot profile`},
+		{"plesk", nil, "the plesk boot profile is not available for server 321"},
+	}
+	for _, c := range cases {
+		err := checkBootOptions(boot, testAccServerNumber, c.profile, testBootConfig(c.arguments))
+		if c.err == "" && err != nil || c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s %v: expected %q, got %v", c.profile, c.arguments, c.err, err)
+		}
+	}
+
+	// an active profile lists no options, so its arguments aren't checked
+	if _, err := c.Boot.ActivateRescue(ctx, testAccServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+	if boot, err = c.Boot.Get(ctx, testAccServerNumber); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bootProfileOptions(boot)[robot.BootProfileRescue]; ok {
+		t.Fatal("expected no options of the active rescue profile")
+	}
+	if err := checkBootOptions(boot, testAccServerNumber, robot.BootProfileRescue, testBootConfig(map[string]cty.Value{"keyboard": cty.StringVal("dvorak")})); err != nil {
+		t.Fatalf("expected the active profile not to be checked, got %v", err)
+	}
+}
+
+// testAccAddPanelServer adds a second server to fake, offering the Plesk and cPanel installations.
+func testAccAddPanelServer(fake *robottest.Server) {
+	fake.AddServer(robot.Server{
+		ServerNumber: testAccPanelServerNumber,
+		ServerIP:     "123.123.123.124",
+		ServerName:   "acc-test-panel",
+		Product:      "AX41",
+		DataCenter:   "FSN1-DC14",
+		VNC:          true,
+		Windows:      true,
+		Plesk:        true,
+		CPanel:       true,
+	})
+}
+
+func TestSetBootProfile(t *testing.T) {
+	fake := testAccFake(t)
+	testAccAddPanelServer(fake)
+	c := HetznerRobotClient{Client: fake.Client()}
+	ctx := context.Background()
+
+	profiles := []BootProfile{
+		{ActiveProfile: robot.BootProfileVNC, OperatingSystem: "Debian-12", Architecture: "64", Language: "en_US"},
+		{ActiveProfile: robot.BootProfileWindows, OperatingSystem: "standard", Language: "de"},
+		{ActiveProfile: robot.BootProfilePlesk, OperatingSystem: "Debian 12 base", Architecture: "64", Language: "en", Hostname: "plesk.example.com"},
+		{ActiveProfile: robot.BootProfileCPanel, OperatingSystem: "AlmaLinux 8 + cPanel", Architecture: "64", Language: "en", Hostname: "cpanel.example.com"},
+	}
+	for _, profile := range profiles {
+		activated, err := c.setBootProfile(ctx, testAccPanelServerNumber, profile)
+		if err != nil {
+			t.Fatalf("unable to activate %s: %v", profile.ActiveProfile, err)
+		}
+		boot, err := c.getBoot(ctx, testAccPanelServerNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, got := range []*BootProfile{activated, boot} {
+			if got.ActiveProfile != profile.ActiveProfile || got.OperatingSystem != profile.OperatingSystem || got.Architecture != profile.Architecture ||
+				got.Language != profile.Language || got.Hostname != profile.Hostname || got.Password == "" || got.ServerID != testAccPanelServerNumber {
+				t.Fatalf("expected %+v to read back, got %+v", profile, got)
+			}
+		}
+		if err := c.unsetBootProfile(ctx, testAccPanelServerNumber, profile.ActiveProfile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the server offers no Plesk installation
+	if _, err := c.setBootProfile(ctx, testAccServerNumber, profiles[2]); err == nil {
+		t.Fatal("expected plesk to be refused on a server without it")
+	}
+}
+
+func TestAccBoot_profiles(t *testing.T) {
+	fake := testAccFake(t)
+	testAccAddPanelServer(fake)
+	config := func(profile string) string {
+		return testAccProviderConfig(fake) + fmt.Sprintf(`
+resource "hetzner-robot_boot" "test" {
+  server_id = %d
+%s}
+`, testAccPanelServerNumber, profile)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckBootProfile(fake, testAccPanelServerNumber, ""),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  active_profile   = "vnc"
+  operating_system = "Debian-12"
+  architecture     = "64"
+  language         = "en_US"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "vnc"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "operating_system", "Debian-12"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "architecture", "64"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "language", "en_US"),
+					resource.TestCheckResourceAttrSet("hetzner-robot_boot.test", "password"),
+				),
+			},
+			{
+				Config: config(`
+  active_profile   = "windows"
+  operating_system = "standard"
+  language         = "de"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "windows"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "operating_system", "standard"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "language", "de"),
+				),
+			},
+			{
+				Config: config(`
+  active_profile   = "plesk"
+  operating_system = "Debian 12 base"
+  architecture     = "64"
+  language         = "en"
+  hostname         = "plesk.example.com"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "plesk"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "hostname", "plesk.example.com"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_boot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: config(`
+  active_profile   = "cpanel"
+  operating_system = "AlmaLinux 8 + cPanel"
+  architecture     = "64"
+  language         = "en"
+  hostname         = "cpanel.example.com"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "cpanel"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "operating_system", "AlmaLinux 8 + cPanel"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "hostname", "cpanel.example.com"),
+				),
+			},
+			{
+				Config: config(`
+  active_profile   = "cpanel"
+  operating_system = "AlmaLinux 8 + cPanel"
+  architecture     = "64"
+  language         = "en"
+`),
+				ExpectError: regexp.MustCompile("hostname is required by the cpanel boot profile"),
+			},
+			{
+				Config: config(`
+  active_profile   = "windows"
+  operating_system = "standard"
+  language         = "de"
+  architecture     = "64"
+`),
+				ExpectError: regexp.MustCompile("architecture is not supported by the windows boot profile"),
+			},
+		},
+	})
+}
+
+// testAccCheckBootHostKeyFingerprints checks the host keys of a hetzner-robot_boot resource or data source against the ones
+// of the active rescue system.
+func testAccCheckBootHostKeyFingerprints(fake *robottest.Server, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rescue, err := fake.Client().Boot.GetRescue(context.Background(), testAccServerNumber)
+		if err != nil {
+			return err
+		}
+		if len(rescue.HostKeys) != 1 {
+			return fmt.Errorf("expected one host key, got %v", rescue.HostKeys)
+		}
+		return resource.TestCheckResourceAttr(name, "host_key_fingerprints.0", rescue.HostKeys[0].Fingerprint)(s)
+	}
+}
+
+// testAccCheckBootProfile checks the profile active on a server, "" for none.
+func testAccCheckBootProfile(fake *robottest.Server, serverNumber int, profile string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		c := HetznerRobotClient{Client: fake.Client()}
+		boot, err := c.getBoot(context.Background(), serverNumber)
+		if err != nil {
+			return err
+		}
+		if boot.ActiveProfile != profile {
+			return fmt.Errorf("expected active boot profile %q, got %q", profile, boot.ActiveProfile)
+		}
+		return nil
+	}
+}
+
+func testAccBootConfig(fake *robottest.Server, profile string) string {
+	return testAccProviderConfig(fake) + fmt.Sprintf(`
+resource "hetzner-robot_boot" "test" {
+  server_id = %d
+%s}
+`, testAccServerNumber, profile)
+}
+
+// testAccSSHServer runs an SSH server on a loopback port in place of the installing or installed system, presenting
+// the host keys hostKeys returns for each connection, and returns its address. It ends every handshake before the
+// authentication.
+func testAccSSHServer(t *testing.T, hostKeys func() []ssh.Signer) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			config := &ssh.ServerConfig{
+				PasswordCallback: func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) {
+					return nil, fmt.Errorf("permission denied")
+				},
+			}
+			for _, signer := range hostKeys() {
+				config.AddHostKey(signer)
+			}
+			go func() {
+				defer conn.Close()
+				ssh.NewServerConn(conn, config)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// testAccProtoV5ProviderFactoriesSSH are testAccProtoV5ProviderFactories with the installed systems answering SSH on
+// the port of address, e.g. of testAccSSHServer.
+func testAccProtoV5ProviderFactoriesSSH(t *testing.T, address string) map[string]func() (tfprotov5.ProviderServer, error) {
+	t.Helper()
+
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]func() (tfprotov5.ProviderServer, error){
+		"hetzner-robot": func() (tfprotov5.ProviderServer, error) {
+			sdkProvider := Provider("test")
+			configure := sdkProvider.ConfigureContextFunc
+			sdkProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+				meta, diags := configure(ctx, d)
+				if client, ok := meta.(HetznerRobotClient); ok {
+					client.sshPort = port
+					meta = client
+				}
+				return meta, diags
+			}
+			server, err := providerServer(context.Background(), "test", sdkProvider)
+			if err != nil {
+				return nil, err
+			}
+			return server(), nil
+		},
+	}
+}
+
+// testHostKey returns a new host key, e.g. of the installer.
+func testHostKey(t *testing.T) ssh.Signer {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestAccBoot_waitForInstall(t *testing.T) {
+	fake := testAccFake(t)
+	fake.AddServer(robot.Server{
+		ServerNumber: testAccLocalServerNumber,
+		ServerIP:     "127.0.0.1",
+		ServerName:   "acc-test-local",
+		Product:      "AX41",
+		DataCenter:   "FSN1-DC14",
+		Rescue:       true,
+	})
+	fake.SetProcessingReads(1)
+
+	// the installer answers the first connection, the installed system the ones after it
+	installer := testHostKey(t)
+	var connections atomic.Int32
+	address := testAccSSHServer(t, func() []ssh.Signer {
+		if connections.Add(1) == 1 {
+			return []ssh.Signer{installer}
+		}
+		return fake.LastHostKeys(testAccLocalServerNumber, robot.BootProfileLinux)
+	})
+	config := func(profile string) string {
+		return testAccProviderConfig(fake) + fmt.Sprintf(`
+resource "hetzner-robot_boot" "test" {
+  server_id = %d
+%s}
+`, testAccLocalServerNumber, profile)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactoriesSSH(t, address),
+		CheckDestroy:             testAccCheckBootProfile(fake, testAccLocalServerNumber, ""),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  active_profile   = "rescue"
+  operating_system = "linux"
+  reset_type       = "hw"
+  wait_for_install = true
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`wait_for_install is not supported by the rescue boot profile`),
+			},
+			{
+				Config: config(`
+  active_profile   = "linux"
+  operating_system = "Debian 12 base"
+  language         = "en"
+  reset_type       = "hw"
+  wait_for_install = true
+`),
+				Check: resource.ComposeTestCheckFunc(
+					// the installation consumed the profile
+					testAccCheckBootProfile(fake, testAccLocalServerNumber, ""),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "active_profile", "linux"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "ipv4_address", "127.0.0.1"),
+					func(*terraform.State) error {
+						if n := connections.Load(); n < 2 {
+							return fmt.Errorf("expected the wait to go on past the installer, got %d connections", n)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestWaitForInstall(t *testing.T) {
+	installed := testHostKey(t)
+	fingerprints := []string{ssh.FingerprintLegacyMD5(installed.PublicKey())}
+	ctx := context.Background()
+
+	if err := waitForInstall(ctx, testAccServerNumber, "127.0.0.1:22", nil, time.Second); err == nil || !strings.Contains(err.Error(), "no host keys") {
+		t.Fatalf("expected an error without host keys, got %v", err)
+	}
+
+	// the installer answers on SSH, with a host key of its own
+	installer := testHostKey(t)
+	address := testAccSSHServer(t, func() []ssh.Signer { return []ssh.Signer{installer} })
+	err := waitForInstall(ctx, testAccServerNumber, address, fingerprints, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "timeout waiting for the linux installation on server 321 to finish") ||
+		!strings.Contains(err.Error(), "isn't one of the installation") {
+		t.Fatalf("expected a timeout error naming the host key, got %v", err)
+	}
+
+	address = testAccSSHServer(t, func() []ssh.Signer { return []ssh.Signer{installed} })
+	if err := waitForInstall(ctx, testAccServerNumber, address, fingerprints, time.Second); err != nil {
+		t.Fatalf("expected the installed system to be recognized, got %v", err)
+	}
+}
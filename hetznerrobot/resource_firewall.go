@@ -3,10 +3,14 @@ package hetznerrobot
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
 func resourceFirewall() *schema.Resource {
@@ -18,6 +22,12 @@ func resourceFirewall() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceFirewallImportState,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"server_ip": {
 				Type:     schema.TypeString,
@@ -84,7 +94,7 @@ func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m
 
 	firewallID := d.Id()
 
-	firewall, err := c.getFirewall(ctx, firewallID)
+	firewall, err := c.Firewall.Get(ctx, firewallID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find firewall with ID %s: %s", firewallID, err)
 	}
@@ -111,9 +121,9 @@ func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m
 
 	d.Set("active", active)
 	d.Set("rule", rules)
-	d.Set("server_ip", firewall.IP)
-	d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
-	d.SetId(firewall.IP)
+	d.Set("server_ip", firewall.ServerIP)
+	d.Set("whitelist_hos", firewall.WhitelistHOS)
+	d.SetId(firewall.ServerIP)
 
 	results := make([]*schema.ResourceData, 1)
 	results[0] = d
@@ -130,32 +140,36 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 		status = "active"
 	}
 
-	rules := make([]HetznerRobotFirewallRule, 0)
+	rules := make([]robot.FirewallRule, 0)
 	for _, ruleMap := range d.Get("rule").([]interface{}) {
 		ruleProperties := ruleMap.(map[string]interface{})
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:     ruleProperties["name"].(string),
-			SrcIP:    ruleProperties["src_ip"].(string),
-			SrcPort:  ruleProperties["src_port"].(string),
-			DstIP:    ruleProperties["dst_ip"].(string),
-			DstPort:  ruleProperties["dst_port"].(string),
-			Protocol: ruleProperties["protocol"].(string),
-			TCPFlags: ruleProperties["tcp_flags"].(string),
-			Action:   ruleProperties["action"].(string),
+		rules = append(rules, robot.FirewallRule{
+			IPVersion: "ipv4",
+			Name:      ruleProperties["name"].(string),
+			SrcIP:     ruleProperties["src_ip"].(string),
+			SrcPort:   ruleProperties["src_port"].(string),
+			DstIP:     ruleProperties["dst_ip"].(string),
+			DstPort:   ruleProperties["dst_port"].(string),
+			Protocol:  ruleProperties["protocol"].(string),
+			TCPFlags:  ruleProperties["tcp_flags"].(string),
+			Action:    ruleProperties["action"].(string),
 		})
 	}
 
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
+	if _, err := c.Firewall.Update(ctx, serverIP, robot.FirewallRequest{
+		Status:       status,
+		WhitelistHOS: d.Get("whitelist_hos").(bool),
+		Rules:        robot.FirewallRules{Input: rules},
 	}); err != nil {
-		return diag.FromErr(err)
+		return errorDiagnostics(fmt.Sprintf("Unable to update firewall of %s", serverIP), err, firewallParamPaths)
 	}
 
 	d.SetId(serverIP)
 
+	if err := waitForFirewall(ctx, c, serverIP, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
@@ -167,8 +181,15 @@ func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	serverIP := d.Id()
 
-	firewall, err := c.getFirewall(ctx, serverIP)
+	firewall, err := c.Firewall.Get(ctx, serverIP)
 	if err != nil {
+		if robot.IsNotFound(err) {
+			tflog.Warn(ctx, "firewall not found, removing it from state", map[string]interface{}{
+				"server_ip": serverIP,
+			})
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(err)
 	}
 
@@ -193,8 +214,8 @@ func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 	d.Set("active", active)
 	d.Set("rule", rules)
-	d.Set("server_ip", firewall.IP)
-	d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
+	d.Set("server_ip", firewall.ServerIP)
+	d.Set("whitelist_hos", firewall.WhitelistHOS)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -212,27 +233,30 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		status = "active"
 	}
 
-	rules := make([]HetznerRobotFirewallRule, 0)
+	rules := make([]robot.FirewallRule, 0)
 	for _, ruleMap := range d.Get("rule").([]interface{}) {
 		ruleProperties := ruleMap.(map[string]interface{})
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:     ruleProperties["name"].(string),
-			SrcIP:    ruleProperties["src_ip"].(string),
-			SrcPort:  ruleProperties["src_port"].(string),
-			DstIP:    ruleProperties["dst_ip"].(string),
-			DstPort:  ruleProperties["dst_port"].(string),
-			Protocol: ruleProperties["protocol"].(string),
-			TCPFlags: ruleProperties["tcp_flags"].(string),
-			Action:   ruleProperties["action"].(string),
+		rules = append(rules, robot.FirewallRule{
+			IPVersion: "ipv4",
+			Name:      ruleProperties["name"].(string),
+			SrcIP:     ruleProperties["src_ip"].(string),
+			SrcPort:   ruleProperties["src_port"].(string),
+			DstIP:     ruleProperties["dst_ip"].(string),
+			DstPort:   ruleProperties["dst_port"].(string),
+			Protocol:  ruleProperties["protocol"].(string),
+			TCPFlags:  ruleProperties["tcp_flags"].(string),
+			Action:    ruleProperties["action"].(string),
 		})
 	}
 
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
+	if _, err := c.Firewall.Update(ctx, serverIP, robot.FirewallRequest{
+		Status:       status,
+		WhitelistHOS: d.Get("whitelist_hos").(bool),
+		Rules:        robot.FirewallRules{Input: rules},
 	}); err != nil {
+		return errorDiagnostics(fmt.Sprintf("Unable to update firewall of %s", serverIP), err, firewallParamPaths)
+	}
+	if err := waitForFirewall(ctx, c, serverIP, d.Timeout(schema.TimeoutUpdate)); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -248,3 +272,25 @@ func resourceFirewallDelete(ctx context.Context, d *schema.ResourceData, m inter
 
 	return diags
 }
+
+// waitForFirewall waits until Robot has applied the last change to the firewall of serverIP, which takes up to a
+// minute during which the firewall reports "in process" and refuses further changes.
+func waitForFirewall(ctx context.Context, c HetznerRobotClient, serverIP string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"in process"},
+		Target:  []string{"active", "disabled"},
+		Refresh: func() (interface{}, string, error) {
+			firewall, err := c.Firewall.Get(ctx, serverIP)
+			if err != nil {
+				return nil, "", err
+			}
+			return firewall, firewall.Status, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for firewall of %s to be applied: %w", serverIP, err)
+	}
+	return nil
+}
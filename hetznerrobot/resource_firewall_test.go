@@ -0,0 +1,102 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+func TestAccFirewall(t *testing.T) {
+	fake := testAccFake(t)
+	// Robot applies firewall changes asynchronously
+	fake.SetProcessingReads(1)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFirewallConfig(fake, "22"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "id", testAccServerIP),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "active", "true"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "whitelist_hos", "false"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.#", "2"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.0.dst_port", "22"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.1.action", "discard"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_firewall.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccFirewallConfig(fake, "2222"),
+				Check:  resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.0.dst_port", "2222"),
+			},
+			{
+				// drift: the firewall is disabled outside of Terraform
+				PreConfig: func() {
+					_, err := fake.Client().Firewall.Update(context.Background(), testAccServerIP, robot.FirewallRequest{
+						Status: "disabled",
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccFirewallConfig(fake, "2222"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccFirewallConfig(fake, "2222"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "active", "true"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFirewall_invalidRule(t *testing.T) {
+	fake := testAccFake(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFirewallConfig(fake, "ssh"),
+				// the diagnostic points at the dst_port of the first rule
+				ExpectError: regexp.MustCompile(`(?s)Invalid value.*dst_port = "ssh".*rules\[input\]\[0\]\[dst_port\]`),
+			},
+		},
+	})
+}
+
+func testAccFirewallConfig(fake *robottest.Server, sshPort string) string {
+	return testAccProviderConfig(fake) + fmt.Sprintf(`
+resource "hetzner-robot_firewall" "test" {
+  server_ip     = %q
+  active        = true
+  whitelist_hos = false
+
+  rule {
+    name     = "ssh"
+    dst_port = %q
+    protocol = "tcp"
+    action   = "accept"
+  }
+
+  rule {
+    name   = "deny all"
+    action = "discard"
+  }
+}
+`, testAccServerIP, sshPort)
+}
@@ -2,151 +2,281 @@ package hetznerrobot
 
 import (
 	"context"
-	"errors"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"fmt"
 	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
-func resourceSshKey() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceSshKeyCreate,
-		ReadContext:   resourceSshKeyRead,
-		UpdateContext: resourceSshKeyUpdate,
-		DeleteContext: resourceSshKeyDelete,
+const sshKeyDefaultTimeout = 5 * time.Minute
 
-		Importer: &schema.ResourceImporter{
-			StateContext: resourceSshKeyImportState,
-		},
+var sshKeyFingerprint = regexp.MustCompile(`^[0-9a-f]{2}(:[0-9a-f]{2}){15}$`)
+
+// sshKeyResource is hetzner-robot_ssh_key, the first resource ported to the framework. Its schema and state are
+// those of the SDKv2 resource it replaces.
+type sshKeyResource struct {
+	client HetznerRobotClient
+}
+
+type sshKeyModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Data        types.String   `tfsdk:"data"`
+	Fingerprint types.String   `tfsdk:"fingerprint"`
+	Type        types.String   `tfsdk:"type"`
+	Size        types.Int64    `tfsdk:"size"`
+	CreatedAt   types.String   `tfsdk:"created_at"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+var (
+	_ resource.ResourceWithConfigure   = &sshKeyResource{}
+	_ resource.ResourceWithImportState = &sshKeyResource{}
+)
+
+func newSshKeyResource() resource.Resource {
+	return &sshKeyResource{}
+}
+
+func (r *sshKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_key"
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:        schema.TypeString,
+func (r *sshKeyResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Key name",
 			},
-			"data": {
-				Type:        schema.TypeString,
+			"data": schema.StringAttribute{
 				Required:    true,
 				Description: "Key data in OpenSSH or SSH2 format",
-				ForceNew:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"fingerprint": {
-				Type:        schema.TypeString,
+			"fingerprint": schema.StringAttribute{
 				Computed:    true,
 				Description: "Key fingerprint",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"type": {
-				Type:        schema.TypeString,
+			"type": schema.StringAttribute{
 				Computed:    true,
 				Description: "Key algorithm type",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"size": {
-				Type:        schema.TypeInt,
+			"size": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Key size in bits",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
-			"created_at": {
-				Type:        schema.TypeString,
+			"created_at": schema.StringAttribute{
 				Computed:    true,
 				Description: "Creation date",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
-func resourceSshKeyImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
-
-	keyFingerprint := d.Id()
-	match, err := regexp.Match(`^[0-9a-f]{2}(:[0-9a-f]{2}){15}$`, []byte(keyFingerprint))
-	if err != nil {
-		return nil, err
+func (r *sshKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
 	}
-	if !match {
-		return nil, errors.New("invalid key fingerprint format")
+	client, ok := req.ProviderData.(HetznerRobotClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected HetznerRobotClient, got %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
 	}
+	r.client = client
+}
 
-	key, err := c.getSshKey(ctx, keyFingerprint)
-	if err != nil {
-		return nil, err
+// sshKeyContext labels the webservice requests with the resource, like withCaller does for the SDKv2 resources.
+func sshKeyContext(ctx context.Context, fingerprint string) context.Context {
+	caller := "hetzner-robot_ssh_key"
+	if fingerprint != "" {
+		caller += " " + fingerprint
 	}
+	return robot.ContextWithCaller(ctx, caller)
+}
 
-	d.Set("name", key.Name)
-	d.Set("data", key.Data)
-	d.Set("fingerprint", key.Fingerprint)
-	d.Set("type", key.Type)
-	d.Set("size", key.Size)
-	d.Set("created_at", key.CreatedAt)
+func (m *sshKeyModel) set(key *robot.Key) {
+	m.ID = types.StringValue(key.Fingerprint)
+	m.Name = types.StringValue(key.Name)
+	m.Data = types.StringValue(key.Data)
+	m.Fingerprint = types.StringValue(key.Fingerprint)
+	m.Type = types.StringValue(key.Type)
+	m.Size = types.Int64Value(int64(key.Size))
+	m.CreatedAt = types.StringValue(key.CreatedAt)
+}
 
-	return []*schema.ResourceData{d}, nil
+func (r *sshKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !sshKeyFingerprint.MatchString(req.ID) {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("Expected the key fingerprint, e.g. 56:29:99:a4:5d:ed:ac:95:c1:f5:88:82:90:5d:dd:10, got %q", req.ID))
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func resourceSshKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan sshKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	name := d.Get("name").(string)
-	data := d.Get("data").(string)
+	timeout, diags := plan.Timeouts.Create(ctx, sshKeyDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(sshKeyContext(ctx, ""), timeout)
+	defer cancel()
 
-	key, err := c.createSshKey(ctx, name, data)
+	name := plan.Name.ValueString()
+	data := plan.Data.ValueString()
+
+	request := robot.KeyCreateRequest{Name: name, Data: data}
+	started := time.Now()
+	key, err := r.client.Keys.Create(ctx, request)
+	if err != nil {
+		key, err = recoverSshKey(ctx, r.client.Client, request, started, err)
+	}
+	if robot.HasErrorCode(err, "KEY_ALREADY_EXISTS") {
+		fingerprint, _ := robot.KeyFingerprint(data)
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to create SSH key %q", name),
+			fmt.Sprintf("The key already exists in Robot, import it with the ID %q", fingerprint))
+		return
+	}
 	if err != nil {
-		return diag.Errorf("Unable to create SSH key %q:\n\t %q", name, err)
+		resp.Diagnostics.Append(frameworkDiagnostics(errorDiagnostics(fmt.Sprintf("Unable to create SSH key %q", name), err, sshKeyParamPaths))...)
+		return
 	}
 
-	d.Set("fingerprint", key.Fingerprint)
-	d.Set("type", key.Type)
-	d.Set("size", key.Size)
-	d.Set("created_at", key.CreatedAt)
-	d.SetId(key.Fingerprint)
+	plan.ID = types.StringValue(key.Fingerprint)
+	plan.Fingerprint = types.StringValue(key.Fingerprint)
+	plan.Type = types.StringValue(key.Type)
+	plan.Size = types.Int64Value(int64(key.Size))
+	plan.CreatedAt = types.StringValue(key.CreatedAt)
 
-	return diag.Diagnostics{}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state sshKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyFingerprint := state.ID.ValueString()
 
-	keyFingerprint := d.Id()
+	timeout, diags := state.Timeouts.Read(ctx, sshKeyDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(sshKeyContext(ctx, keyFingerprint), timeout)
+	defer cancel()
 
-	key, err := c.getSshKey(ctx, keyFingerprint)
+	key, err := r.client.Keys.Get(ctx, keyFingerprint)
 	if err != nil {
-		return diag.Errorf("Unable to find SSH key %q:\n\t %q", keyFingerprint, err)
+		if robot.IsNotFound(err) {
+			tflog.Warn(ctx, "SSH key not found, removing it from state", map[string]interface{}{
+				"fingerprint": keyFingerprint,
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to find SSH key %q", keyFingerprint), err.Error())
+		return
 	}
 
-	d.Set("name", key.Name)
-	d.Set("data", key.Data)
-	d.Set("fingerprint", key.Fingerprint)
-	d.Set("type", key.Type)
-	d.Set("size", key.Size)
-	d.Set("created_at", key.CreatedAt)
+	state.set(key)
 
-	return diag.Diagnostics{}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func resourceSshKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan sshKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	keyFingerprint := d.Id()
-	name := d.Get("name").(string)
+	keyFingerprint := plan.ID.ValueString()
 
-	key, err := c.updateSshKey(ctx, keyFingerprint, name)
+	timeout, diags := plan.Timeouts.Update(ctx, sshKeyDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(sshKeyContext(ctx, keyFingerprint), timeout)
+	defer cancel()
+
+	key, err := r.client.Keys.Rename(ctx, keyFingerprint, plan.Name.ValueString())
 	if err != nil {
-		return diag.Errorf("Unable to update SSH key %q:\n\t %q", keyFingerprint, err)
+		resp.Diagnostics.Append(frameworkDiagnostics(errorDiagnostics(fmt.Sprintf("Unable to update SSH key %q", keyFingerprint), err, sshKeyParamPaths))...)
+		return
 	}
 
-	d.Set("name", key.Name)
+	plan.Name = types.StringValue(key.Name)
 
-	return diag.Diagnostics{}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceSshKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state sshKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	keyFingerprint := d.Id()
+	keyFingerprint := state.ID.ValueString()
 
-	err := c.deleteSshKey(ctx, keyFingerprint)
-	if err != nil {
-		return diag.Errorf("Unable to delete SSH key %q:\n\t %q", keyFingerprint, err)
+	timeout, diags := state.Timeouts.Delete(ctx, sshKeyDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(sshKeyContext(ctx, keyFingerprint), timeout)
+	defer cancel()
 
-	return diag.Diagnostics{}
+	if err := r.client.Keys.Delete(ctx, keyFingerprint); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to delete SSH key %q", keyFingerprint), err.Error())
+	}
 }
@@ -0,0 +1,289 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+func TestAccSshKey(t *testing.T) {
+	fake := testAccFake(t)
+	fingerprint, err := robot.KeyFingerprint(testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckSshKeyDestroyed(fake, fingerprint),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSshKeyConfig(fake, "deploy"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "id", fingerprint),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "fingerprint", fingerprint),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "name", "deploy"),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "type", "ED25519"),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "size", "256"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_ssh_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSshKeyConfig(fake, "deploy-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "id", fingerprint),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "name", "deploy-renamed"),
+				),
+			},
+			{
+				// drift: the key is renamed outside of Terraform
+				PreConfig: func() {
+					if _, err := fake.Client().Keys.Rename(context.Background(), fingerprint, "renamed-by-hand"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccSshKeyConfig(fake, "deploy-renamed"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccSshKeyConfig(fake, "deploy-renamed"),
+				Check:  resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "name", "deploy-renamed"),
+			},
+			{
+				// drift: the key is deleted outside of Terraform and gets recreated
+				PreConfig: func() {
+					if err := fake.Client().Keys.Delete(context.Background(), fingerprint); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccSshKeyConfig(fake, "deploy-renamed"),
+				Check:  resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "fingerprint", fingerprint),
+			},
+		},
+	})
+}
+
+func TestAccSshKey_lostCreateResponse(t *testing.T) {
+	fake := testAccFake(t)
+	fingerprint, err := robot.KeyFingerprint(testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckSshKeyDestroyed(fake, fingerprint),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					fake.InjectFault(robottest.LostResponse(http.MethodPost, "/key", 1))
+				},
+				Config: testAccSshKeyConfig(fake, "deploy"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "id", fingerprint),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "name", "deploy"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccSshKey_upgradeFromSDKv2 applies with the last release, whose hetzner-robot_ssh_key was an SDKv2 resource,
+// and plans with the framework resource of the mux. The release is downloaded from the Terraform registry, so the
+// test only runs with TF_ACC_REGISTRY set.
+func TestAccSshKey_upgradeFromSDKv2(t *testing.T) {
+	if os.Getenv("TF_ACC_REGISTRY") == "" {
+		t.Skip("set TF_ACC_REGISTRY to run tests downloading providers from the Terraform registry")
+	}
+	fake := testAccFake(t)
+	fingerprint, err := robot.KeyFingerprint(testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the last release only knows the credentials and the URL
+	config := fmt.Sprintf(`
+provider "hetzner-robot" {
+  username = %q
+  password = %q
+  url      = %q
+}
+
+resource "hetzner-robot_ssh_key" "test" {
+  name = "deploy"
+  data = %q
+}
+`, fake.Username, fake.Password, fake.URL, testAccKey)
+
+	resource.Test(t, resource.TestCase{
+		CheckDestroy: testAccCheckSshKeyDestroyed(fake, fingerprint),
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"hetzner-robot": {
+						Source:            "strng-solutions/hetzner-robot",
+						VersionConstraint: "0.1.0",
+					},
+				},
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "id", fingerprint),
+			},
+			{
+				ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+				Config:                   config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+// testSshKeyState returns the state of the framework hetzner-robot_ssh_key with the given ID and timeouts,
+// the other attributes are null.
+func testSshKeyState(t *testing.T, r *sshKeyResource, id string, timeoutValues map[string]string) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+
+	attrTypes := map[string]attr.Type{"create": types.StringType, "read": types.StringType, "update": types.StringType, "delete": types.StringType}
+	attrValues := make(map[string]attr.Value, len(attrTypes))
+	for name := range attrTypes {
+		attrValues[name] = types.StringNull()
+		if value, ok := timeoutValues[name]; ok {
+			attrValues[name] = types.StringValue(value)
+		}
+	}
+	model := sshKeyModel{
+		ID:          types.StringValue(id),
+		Name:        types.StringNull(),
+		Data:        types.StringNull(),
+		Fingerprint: types.StringNull(),
+		Type:        types.StringNull(),
+		Size:        types.Int64Null(),
+		CreatedAt:   types.StringNull(),
+		Timeouts:    timeouts.Value{Object: types.ObjectValueMust(attrTypes, attrValues)},
+	}
+	if diags := state.Set(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	return state
+}
+
+func TestSshKeyImportState(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &sshKeyResource{client: HetznerRobotClient{Client: fake.Client()}}
+	ctx := context.Background()
+
+	// the state terraform imports into is null
+	empty := testSshKeyState(t, r, "", nil)
+	empty.Raw = tftypes.NewValue(empty.Schema.Type().TerraformType(ctx), nil)
+	importResp := &fwresource.ImportStateResponse{State: empty}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "deploy"}, importResp)
+	if !importResp.Diagnostics.HasError() || importResp.Diagnostics[0].Summary() != "Invalid import ID" {
+		t.Fatalf("expected the key name to be refused as import ID, got %v", importResp.Diagnostics)
+	}
+
+	importResp = &fwresource.ImportStateResponse{State: empty}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: key.Fingerprint}, importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatal(importResp.Diagnostics)
+	}
+
+	// terraform reads the imported resource right away
+	readResp := &fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatal(readResp.Diagnostics)
+	}
+	var imported sshKeyModel
+	if diags := readResp.State.Get(ctx, &imported); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if imported.ID.ValueString() != key.Fingerprint || imported.Name.ValueString() != "deploy" || imported.Data.ValueString() != testAccKey ||
+		imported.Type.ValueString() != "ED25519" || imported.Size.ValueInt64() != 256 {
+		t.Fatalf("unexpected imported key: %+v", imported)
+	}
+}
+
+func TestSshKeyTimeouts(t *testing.T) {
+	fake := testAccFake(t)
+	key, err := fake.AddKey("deploy", testAccKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// without a deadline, the client would keep retrying for about a minute
+	r := &sshKeyResource{client: HetznerRobotClient{Client: fake.Client(robot.WithRetries(600, 100*time.Millisecond))}}
+	ctx := context.Background()
+	fake.InjectFault(robottest.Unavailable(http.MethodGet, "/key", 0))
+
+	state := testSshKeyState(t, r, key.Fingerprint, map[string]string{"read": "500ms"})
+	readResp := &fwresource.ReadResponse{State: state}
+	start := time.Now()
+	r.Read(ctx, fwresource.ReadRequest{State: state}, readResp)
+	if !readResp.Diagnostics.HasError() {
+		t.Fatal("expected the read to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the read timeout to stop the retries, took %s", elapsed)
+	}
+
+	// an invalid timeout is reported before any request
+	requests := len(fake.Requests())
+	state = testSshKeyState(t, r, key.Fingerprint, map[string]string{"read": "forever"})
+	readResp = &fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, readResp)
+	if !readResp.Diagnostics.HasError() || len(fake.Requests()) != requests {
+		t.Fatalf("expected the invalid timeout to be reported, got %v", readResp.Diagnostics)
+	}
+}
+
+func testAccSshKeyConfig(fake *robottest.Server, name string) string {
+	return testAccProviderConfig(fake) + fmt.Sprintf(`
+resource "hetzner-robot_ssh_key" "test" {
+  name = %q
+  data = %q
+}
+`, name, testAccKey)
+}
+
+func testAccCheckSshKeyDestroyed(fake *robottest.Server, fingerprint string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		_, err := fake.Client().Keys.Get(context.Background(), fingerprint)
+		if !robot.IsNotFound(err) {
+			return fmt.Errorf("expected SSH key %s to be deleted, got %v", fingerprint, err)
+		}
+		return nil
+	}
+}
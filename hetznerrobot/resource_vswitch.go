@@ -3,9 +3,12 @@ package hetznerrobot
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 	"strconv"
+	"time"
 )
 
 func resourceVSwitch() *schema.Resource {
@@ -18,6 +21,12 @@ func resourceVSwitch() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceVSwitchImportState,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -38,7 +47,7 @@ func resourceVSwitch() *schema.Resource {
 			},
 			"servers": {
 				Type:        schema.TypeList,
-				Description: "Attached server list",
+				Description: "Attached server list, the servers are attached when the vSwitch is created and when the list changes",
 				Optional:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -113,18 +122,21 @@ func resourceVSwitch() *schema.Resource {
 func resourceVSwitchImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	c := meta.(HetznerRobotClient)
 
-	vSwitchID := d.Id()
-	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	vSwitchID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("invalid vSwitch ID %q: %s", d.Id(), err)
+	}
+	vSwitch, err := c.VSwitch.Get(ctx, vSwitchID)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to find VSwitch with ID %d:\n\t %q", vSwitchID, err)
 	}
 
 	d.Set("name", vSwitch.Name)
-	d.Set("vlan", vSwitch.Vlan)
+	d.Set("vlan", vSwitch.VLAN)
 	d.Set("is_cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
+	d.Set("servers", flattenVSwitchServers(vSwitch.Servers))
+	d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnets))
+	d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetworks))
 
 	results := make([]*schema.ResourceData, 1)
 	results[0] = d
@@ -136,38 +148,55 @@ func resourceVSwitchCreate(ctx context.Context, d *schema.ResourceData, meta int
 
 	name := d.Get("name").(string)
 	vlan := d.Get("vlan").(int)
-	vSwitch, err := c.createVSwitch(ctx, name, vlan)
+	request := robot.VSwitchRequest{Name: name, VLAN: vlan}
+	// the vSwitches that exist already, which a failed create must not adopt
+	existing, err := c.VSwitch.List(ctx)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to create VSwitch :\n\t %q", err))
+		return errorDiagnostics("Unable to create VSwitch", err, vSwitchParamPaths)
+	}
+	vSwitch, err := c.VSwitch.Create(ctx, request)
+	if err != nil {
+		vSwitch, err = recoverVSwitch(ctx, c.Client, request, existing, err)
+	}
+	if err != nil {
+		return errorDiagnostics("Unable to create VSwitch", err, vSwitchParamPaths)
 	}
-
-	d.Set("is_cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
 	d.SetId(strconv.Itoa(vSwitch.ID))
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	if servers := vSwitchServerNumbers(d.Get("servers").([]interface{})); len(servers) > 0 {
+		if err := c.VSwitch.AddServers(ctx, vSwitch.ID, servers); err != nil {
+			return diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
+		}
+	}
 
-	return diags
+	return resourceVSwitchRead(ctx, d, meta)
 }
 
 func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(HetznerRobotClient)
 
-	vSwitchID := d.Id()
-	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	vSwitchID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err))
+		return diag.Errorf("invalid vSwitch ID %q: %s", d.Id(), err)
+	}
+	vSwitch, err := c.VSwitch.Get(ctx, vSwitchID)
+	if err != nil {
+		if robot.IsNotFound(err) {
+			tflog.Warn(ctx, "vSwitch not found, removing it from state", map[string]interface{}{
+				"id": vSwitchID,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %d:\n\t %q", vSwitchID, err))
 	}
 
 	d.Set("name", vSwitch.Name)
-	d.Set("vlan", vSwitch.Vlan)
-	d.Set("cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
+	d.Set("vlan", vSwitch.VLAN)
+	d.Set("is_cancelled", vSwitch.Cancelled)
+	d.Set("servers", flattenVSwitchServers(vSwitch.Servers))
+	d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnets))
+	d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetworks))
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -178,55 +207,36 @@ func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta inter
 func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(HetznerRobotClient)
 
-	vSwitchID := d.Id()
-	name := d.Get("name").(string)
-	vlan := d.Get("vlan").(int)
-	err := c.updateVSwitch(ctx, vSwitchID, name, vlan)
+	vSwitchID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.Errorf("Unable to update VSwitch:\n\t %q", err)
+		return diag.Errorf("invalid vSwitch ID %q: %s", d.Id(), err)
+	}
+	if d.HasChanges("name", "vlan") {
+		name := d.Get("name").(string)
+		vlan := d.Get("vlan").(int)
+		err = c.VSwitch.Update(ctx, vSwitchID, robot.VSwitchRequest{Name: name, VLAN: vlan})
+		if err != nil {
+			return errorDiagnostics("Unable to update VSwitch", err, vSwitchParamPaths)
+		}
 	}
 
 	if d.HasChange("servers") {
 		o, n := d.GetChange("servers")
 
-		oldServers := o.([]interface{})
-		newServers := n.([]interface{})
+		oldServers := vSwitchServerNumbers(o.([]interface{}))
+		newServers := vSwitchServerNumbers(n.([]interface{}))
 
-		mb := make(map[int]struct{}, len(newServers))
-		for _, x := range newServers {
-			srv := x.(map[string]interface{})
-			mb[srv["server_number"].(int)] = struct{}{}
-		}
-		var serversToRemove []HetznerRobotVSwitchServer
-		for _, x := range oldServers {
-			srv := x.(map[string]interface{})
-			srvNum := srv["server_number"].(int)
-			if _, found := mb[srvNum]; !found {
-				serversToRemove = append(serversToRemove, HetznerRobotVSwitchServer{ServerNumber: srvNum})
+		if serversToRemove := missingServers(oldServers, newServers); len(serversToRemove) > 0 {
+			if err := c.VSwitch.RemoveServers(ctx, vSwitchID, serversToRemove); err != nil {
+				return diag.Errorf("Unable to remove servers from VSwitch:\n\t %q", err)
 			}
 		}
 
-		if err := c.removeVSwitchServers(ctx, vSwitchID, serversToRemove); err != nil {
-			diag.Errorf("Unable to remove servers from VSwitch:\n\t %q", err)
-		}
-
-		ma := make(map[int]struct{}, len(oldServers))
-		for _, x := range oldServers {
-			srv := x.(map[string]interface{})
-			ma[srv["server_number"].(int)] = struct{}{}
-		}
-		var serversToAdd []HetznerRobotVSwitchServer
-		for _, x := range newServers {
-			srv := x.(map[string]interface{})
-			srvNum := srv["server_number"].(int)
-			if _, found := ma[srvNum]; !found {
-				serversToAdd = append(serversToAdd, HetznerRobotVSwitchServer{ServerNumber: srvNum})
+		if serversToAdd := missingServers(newServers, oldServers); len(serversToAdd) > 0 {
+			if err := c.VSwitch.AddServers(ctx, vSwitchID, serversToAdd); err != nil {
+				return diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
 			}
 		}
-
-		if err := c.addVSwitchServers(ctx, vSwitchID, serversToAdd); err != nil {
-			diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
-		}
 	}
 
 	return resourceVSwitchRead(ctx, d, meta)
@@ -235,10 +245,13 @@ func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta int
 func resourceVSwitchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(HetznerRobotClient)
 
-	vSwitchID := d.Id()
-	err := c.deleteVSwitch(ctx, vSwitchID)
+	vSwitchID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.Errorf("invalid vSwitch ID %q: %s", d.Id(), err)
+	}
+	err = c.VSwitch.Cancel(ctx, vSwitchID, time.Now().Format("2006-01-02"))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err))
+		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %d:\n\t %q", vSwitchID, err))
 	}
 
 	// Warning or errors can be collected in a slice type
@@ -246,3 +259,65 @@ func resourceVSwitchDelete(ctx context.Context, d *schema.ResourceData, meta int
 
 	return diags
 }
+
+func vSwitchServerNumbers(servers []interface{}) []int {
+	serverNumbers := make([]int, 0, len(servers))
+	for _, x := range servers {
+		srv := x.(map[string]interface{})
+		serverNumbers = append(serverNumbers, srv["server_number"].(int))
+	}
+	return serverNumbers
+}
+
+// missingServers returns the server numbers of a that are not in b.
+func missingServers(a []int, b []int) []int {
+	mb := make(map[int]struct{}, len(b))
+	for _, srvNum := range b {
+		mb[srvNum] = struct{}{}
+	}
+	var missing []int
+	for _, srvNum := range a {
+		if _, found := mb[srvNum]; !found {
+			missing = append(missing, srvNum)
+		}
+	}
+	return missing
+}
+
+func flattenVSwitchServers(servers []robot.VSwitchServer) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(servers))
+	for _, server := range servers {
+		result = append(result, map[string]interface{}{
+			"server_number":   server.ServerNumber,
+			"server_ip":       server.ServerIP,
+			"server_ipv6_net": server.ServerIPv6Net,
+			"status":          server.Status,
+		})
+	}
+	return result
+}
+
+func flattenVSwitchSubnets(subnets []robot.VSwitchSubnet) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(subnets))
+	for _, subnet := range subnets {
+		result = append(result, map[string]interface{}{
+			"ip":      subnet.IP,
+			"mask":    subnet.Mask,
+			"gateway": subnet.Gateway,
+		})
+	}
+	return result
+}
+
+func flattenVSwitchCloudNetworks(cloudNetworks []robot.VSwitchCloudNetwork) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(cloudNetworks))
+	for _, cloudNetwork := range cloudNetworks {
+		result = append(result, map[string]interface{}{
+			"id":      cloudNetwork.ID,
+			"ip":      cloudNetwork.IP,
+			"mask":    cloudNetwork.Mask,
+			"gateway": cloudNetwork.Gateway,
+		})
+	}
+	return result
+}
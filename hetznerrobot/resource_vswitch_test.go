@@ -0,0 +1,143 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+func TestAccVSwitch(t *testing.T) {
+	fake := testAccFake(t)
+	var vSwitchID int
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckVSwitchCancelled(fake, &vSwitchID),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVSwitchConfig(fake, "private", 4000, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccVSwitchID("hetzner-robot_vswitch.test", &vSwitchID),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "name", "private"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "vlan", "4000"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "is_cancelled", "false"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_vswitch.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccVSwitchConfig(fake, "private-renamed", 4001, fmt.Sprintf(`
+  servers {
+    server_number = %d
+  }
+`, testAccServerNumber)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "name", "private-renamed"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "vlan", "4001"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.#", "1"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.0.server_ip", testAccServerIP),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.0.status", "ready"),
+				),
+			},
+			{
+				// drift: the server is removed outside of Terraform
+				PreConfig: func() {
+					if err := fake.Client().VSwitch.RemoveServers(context.Background(), vSwitchID, []int{testAccServerNumber}); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccVSwitchConfig(fake, "private-renamed", 4001, fmt.Sprintf(`
+  servers {
+    server_number = %d
+  }
+`, testAccServerNumber)),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccVSwitchConfig(fake, "private-renamed", 4001, ""),
+				Check:  resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.#", "0"),
+			},
+		},
+	})
+}
+
+func TestAccVSwitch_lostCreateResponse(t *testing.T) {
+	fake := testAccFake(t)
+	var vSwitchID int
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckVSwitchCancelled(fake, &vSwitchID),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					fake.InjectFault(robottest.LostResponse(http.MethodPost, "/vswitch", 1))
+				},
+				Config: testAccVSwitchConfig(fake, "private", 4000, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccVSwitchID("hetzner-robot_vswitch.test", &vSwitchID),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "name", "private"),
+					func(*terraform.State) error {
+						vSwitches, err := fake.Client().VSwitch.List(context.Background())
+						if err != nil {
+							return err
+						}
+						if len(vSwitches) != 1 || vSwitches[0].ID != vSwitchID {
+							return fmt.Errorf("expected the vSwitch of the lost response to be adopted, got %+v", vSwitches)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccVSwitchConfig(fake *robottest.Server, name string, vlan int, servers string) string {
+	return testAccProviderConfig(fake) + fmt.Sprintf(`
+resource "hetzner-robot_vswitch" "test" {
+  name = %q
+  vlan = %d
+%s}
+`, name, vlan, servers)
+}
+
+func testAccVSwitchID(resourceName string, vSwitchID *int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		id, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		*vSwitchID = id
+		return nil
+	}
+}
+
+func testAccCheckVSwitchCancelled(fake *robottest.Server, vSwitchID *int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		vSwitch, err := fake.Client().VSwitch.Get(context.Background(), *vSwitchID)
+		if err != nil && !robot.IsNotFound(err) {
+			return err
+		}
+		if err == nil && !vSwitch.Cancelled {
+			return fmt.Errorf("expected vSwitch %d to be cancelled", *vSwitchID)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package hetznerrobot
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServer serves the SDKv2 provider and the terraform-plugin-framework provider as one, while the resources
+// are ported to the framework one at a time.
+func ProviderServer(ctx context.Context, version string) (func() tfprotov5.ProviderServer, error) {
+	return providerServer(ctx, version, Provider(version))
+}
+
+// providerServer serves sdkProvider, e.g. one a test configures differently, with the framework provider.
+func providerServer(ctx context.Context, version string, sdkProvider *schema.Provider) (func() tfprotov5.ProviderServer, error) {
+	configured := &configuredClient{}
+
+	configure := sdkProvider.ConfigureContextFunc
+	sdkProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		meta, diags := configure(ctx, d)
+		if client, ok := meta.(HetznerRobotClient); ok {
+			configured.client = &client
+		}
+		return meta, diags
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(newFrameworkProvider(version, sdkProvider.Schema, configured)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer, nil
+}
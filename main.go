@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot"
 )
 
@@ -17,19 +19,27 @@ import (
 // can be customized.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
+// version is set by goreleaser at build time.
+var version = "dev"
+
 func main() {
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		Debug:        debug,
-		ProviderAddr: "registry.terraform.io/strng-solutions/hetzner-robot",
-		ProviderFunc: func() *schema.Provider {
-			return hetznerrobot.Provider()
-		},
+	providerServer, err := hetznerrobot.ProviderServer(context.Background(), version)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	plugin.Serve(opts)
+	var serveOpts []tf5server.ServeOpt
+	if debug {
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+
+	err = tf5server.Serve("registry.terraform.io/strng-solutions/hetzner-robot", providerServer, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,324 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#boot-configuration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Boot profiles, as used in the paths of the /boot endpoints.
+const (
+	BootProfileRescue  = "rescue"
+	BootProfileLinux   = "linux"
+	BootProfileVNC     = "vnc"
+	BootProfileWindows = "windows"
+	BootProfilePlesk   = "plesk"
+	BootProfileCPanel  = "cpanel"
+)
+
+// While a profile is inactive, its StringList attributes list the available values;
+// once active they hold the configured value.
+
+type RescueConfig struct {
+	ServerIP       string     `json:"server_ip"`
+	ServerIPv6Net  string     `json:"server_ipv6_net"`
+	ServerNumber   int        `json:"server_number"`
+	OS             StringList `json:"os"`
+	Arch           StringList `json:"arch"`
+	Keyboard       StringList `json:"keyboard"`
+	Active         bool       `json:"active"`
+	Password       string     `json:"password"`
+	AuthorizedKeys KeyList    `json:"authorized_key"`
+	HostKeys       KeyList    `json:"host_key"`
+	BootTime       string     `json:"boot_time"`
+}
+
+type LinuxConfig struct {
+	ServerIP       string     `json:"server_ip"`
+	ServerIPv6Net  string     `json:"server_ipv6_net"`
+	ServerNumber   int        `json:"server_number"`
+	Dist           StringList `json:"dist"`
+	Arch           StringList `json:"arch"`
+	Lang           StringList `json:"lang"`
+	Active         bool       `json:"active"`
+	Password       string     `json:"password"`
+	AuthorizedKeys KeyList    `json:"authorized_key"`
+	HostKeys       KeyList    `json:"host_key"`
+}
+
+type VNCConfig struct {
+	ServerIP      string     `json:"server_ip"`
+	ServerIPv6Net string     `json:"server_ipv6_net"`
+	ServerNumber  int        `json:"server_number"`
+	Dist          StringList `json:"dist"`
+	Arch          StringList `json:"arch"`
+	Lang          StringList `json:"lang"`
+	Active        bool       `json:"active"`
+	Password      string     `json:"password"`
+}
+
+type WindowsConfig struct {
+	ServerIP      string     `json:"server_ip"`
+	ServerIPv6Net string     `json:"server_ipv6_net"`
+	ServerNumber  int        `json:"server_number"`
+	Dist          StringList `json:"dist"`
+	Lang          StringList `json:"lang"`
+	Active        bool       `json:"active"`
+	Password      string     `json:"password"`
+}
+
+// PanelConfig is the configuration of the Plesk and cPanel installations.
+type PanelConfig struct {
+	ServerIP      string     `json:"server_ip"`
+	ServerIPv6Net string     `json:"server_ipv6_net"`
+	ServerNumber  int        `json:"server_number"`
+	Dist          StringList `json:"dist"`
+	Arch          StringList `json:"arch"`
+	Lang          StringList `json:"lang"`
+	Active        bool       `json:"active"`
+	Password      string     `json:"password"`
+	Hostname      string     `json:"hostname"`
+}
+
+// BootConfig holds the boot profiles of a server. Profiles not available for the server are nil.
+type BootConfig struct {
+	Rescue  *RescueConfig  `json:"rescue"`
+	Linux   *LinuxConfig   `json:"linux"`
+	VNC     *VNCConfig     `json:"vnc"`
+	Windows *WindowsConfig `json:"windows"`
+	Plesk   *PanelConfig   `json:"plesk"`
+	CPanel  *PanelConfig   `json:"cpanel"`
+}
+
+type bootConfigResponse struct {
+	Boot BootConfig `json:"boot"`
+}
+
+type RescueRequest struct {
+	OS             string
+	Arch           string
+	Keyboard       string
+	AuthorizedKeys []string // fingerprints
+}
+
+type LinuxRequest struct {
+	Dist           string
+	Arch           string
+	Lang           string
+	AuthorizedKeys []string // fingerprints
+}
+
+type VNCRequest struct {
+	Dist string
+	Arch string
+	Lang string
+}
+
+type WindowsRequest struct {
+	Dist string
+	Lang string
+}
+
+type PanelRequest struct {
+	Dist     string
+	Arch     string
+	Lang     string
+	Hostname string
+}
+
+func setIfNotEmpty(data url.Values, key string, value string) {
+	if value != "" {
+		data.Set(key, value)
+	}
+}
+
+func (r RescueRequest) values() url.Values {
+	data := url.Values{}
+	setIfNotEmpty(data, "os", r.OS)
+	setIfNotEmpty(data, "arch", r.Arch)
+	setIfNotEmpty(data, "keyboard", r.Keyboard)
+	for _, key := range r.AuthorizedKeys {
+		data.Add("authorized_key", key)
+	}
+	return data
+}
+
+func (r LinuxRequest) values() url.Values {
+	data := url.Values{}
+	setIfNotEmpty(data, "dist", r.Dist)
+	setIfNotEmpty(data, "arch", r.Arch)
+	setIfNotEmpty(data, "lang", r.Lang)
+	for _, key := range r.AuthorizedKeys {
+		data.Add("authorized_key", key)
+	}
+	return data
+}
+
+func (r VNCRequest) values() url.Values {
+	data := url.Values{}
+	setIfNotEmpty(data, "dist", r.Dist)
+	setIfNotEmpty(data, "arch", r.Arch)
+	setIfNotEmpty(data, "lang", r.Lang)
+	return data
+}
+
+func (r WindowsRequest) values() url.Values {
+	data := url.Values{}
+	setIfNotEmpty(data, "dist", r.Dist)
+	setIfNotEmpty(data, "lang", r.Lang)
+	return data
+}
+
+func (r PanelRequest) values() url.Values {
+	data := url.Values{}
+	setIfNotEmpty(data, "dist", r.Dist)
+	setIfNotEmpty(data, "arch", r.Arch)
+	setIfNotEmpty(data, "lang", r.Lang)
+	setIfNotEmpty(data, "hostname", r.Hostname)
+	return data
+}
+
+// BootService handles the /boot endpoints.
+type BootService struct {
+	client *Client
+}
+
+// Get returns all boot profiles of a server.
+func (s *BootService) Get(ctx context.Context, serverNumber int) (*BootConfig, error) {
+	response := bootConfigResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/boot/%d", serverNumber), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Boot, nil
+}
+
+// profile requests one profile; its response has the same shape as BootConfig with a single profile set.
+func (s *BootService) profile(ctx context.Context, method string, serverNumber int, profile string, data url.Values) (*BootConfig, error) {
+	config := BootConfig{}
+	if err := s.client.Do(ctx, method, fmt.Sprintf("/boot/%d/%s", serverNumber, profile), data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Deactivate disarms a boot profile (one of the BootProfile constants).
+func (s *BootService) Deactivate(ctx context.Context, serverNumber int, profile string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/boot/%d/%s", serverNumber, profile), nil, nil)
+}
+
+func (s *BootService) GetRescue(ctx context.Context, serverNumber int) (*RescueConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileRescue, nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.Rescue, nil
+}
+
+func (s *BootService) ActivateRescue(ctx context.Context, serverNumber int, request RescueRequest) (*RescueConfig, error) {
+	config, err := s.profile(ctx, http.MethodPost, serverNumber, BootProfileRescue, request.values())
+	if err != nil {
+		return nil, err
+	}
+	return config.Rescue, nil
+}
+
+// GetLastRescue returns the configuration the rescue system was last activated with.
+func (s *BootService) GetLastRescue(ctx context.Context, serverNumber int) (*RescueConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileRescue+"/last", nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.Rescue, nil
+}
+
+func (s *BootService) GetLinux(ctx context.Context, serverNumber int) (*LinuxConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileLinux, nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.Linux, nil
+}
+
+func (s *BootService) ActivateLinux(ctx context.Context, serverNumber int, request LinuxRequest) (*LinuxConfig, error) {
+	config, err := s.profile(ctx, http.MethodPost, serverNumber, BootProfileLinux, request.values())
+	if err != nil {
+		return nil, err
+	}
+	return config.Linux, nil
+}
+
+// GetLastLinux returns the configuration the Linux installation was last activated with.
+func (s *BootService) GetLastLinux(ctx context.Context, serverNumber int) (*LinuxConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileLinux+"/last", nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.Linux, nil
+}
+
+func (s *BootService) GetVNC(ctx context.Context, serverNumber int) (*VNCConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileVNC, nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.VNC, nil
+}
+
+func (s *BootService) ActivateVNC(ctx context.Context, serverNumber int, request VNCRequest) (*VNCConfig, error) {
+	config, err := s.profile(ctx, http.MethodPost, serverNumber, BootProfileVNC, request.values())
+	if err != nil {
+		return nil, err
+	}
+	return config.VNC, nil
+}
+
+func (s *BootService) GetWindows(ctx context.Context, serverNumber int) (*WindowsConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileWindows, nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.Windows, nil
+}
+
+func (s *BootService) ActivateWindows(ctx context.Context, serverNumber int, request WindowsRequest) (*WindowsConfig, error) {
+	config, err := s.profile(ctx, http.MethodPost, serverNumber, BootProfileWindows, request.values())
+	if err != nil {
+		return nil, err
+	}
+	return config.Windows, nil
+}
+
+func (s *BootService) GetPlesk(ctx context.Context, serverNumber int) (*PanelConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfilePlesk, nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.Plesk, nil
+}
+
+func (s *BootService) ActivatePlesk(ctx context.Context, serverNumber int, request PanelRequest) (*PanelConfig, error) {
+	config, err := s.profile(ctx, http.MethodPost, serverNumber, BootProfilePlesk, request.values())
+	if err != nil {
+		return nil, err
+	}
+	return config.Plesk, nil
+}
+
+func (s *BootService) GetCPanel(ctx context.Context, serverNumber int) (*PanelConfig, error) {
+	config, err := s.profile(ctx, http.MethodGet, serverNumber, BootProfileCPanel, nil)
+	if err != nil {
+		return nil, err
+	}
+	return config.CPanel, nil
+}
+
+func (s *BootService) ActivateCPanel(ctx context.Context, serverNumber int, request PanelRequest) (*PanelConfig, error) {
+	config, err := s.profile(ctx, http.MethodPost, serverNumber, BootProfileCPanel, request.values())
+	if err != nil {
+		return nil, err
+	}
+	return config.CPanel, nil
+}
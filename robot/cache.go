@@ -0,0 +1,136 @@
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tidwall/gjson"
+)
+
+// cachedLists describes the list endpoints of the read cache.
+var cachedLists = map[string]cachedListSpec{
+	"key":     {key: "key.fingerprint", complete: true},
+	"server":  {key: "server.server_number"},
+	"vswitch": {key: "id"},
+}
+
+// cachedListSpec gives the JSON path of the key of the items of a list, and whether they carry every field of
+// the single-item endpoint. Only complete lists answer reads with their items: the server list lacks the
+// availability flags and the vSwitch list the servers, subnets and cloud networks, so these lists only answer
+// reads of objects that are gone, and the objects still there are requested individually.
+type cachedListSpec struct {
+	key      string
+	complete bool
+}
+
+// readCache answers single-item reads from the bulk list endpoints, so that refreshing many objects costs
+// one request per endpoint instead of one per object. It lives as long as the provider process and drops a
+// list as soon as a mutating request is sent to the same endpoint family.
+type readCache struct {
+	mu    sync.Mutex
+	lists map[string]*cachedList
+}
+
+type cachedList struct {
+	mu     sync.Mutex
+	loaded bool
+	items  map[string][]byte
+}
+
+func newReadCache() *readCache {
+	return &readCache{
+		lists: make(map[string]*cachedList),
+	}
+}
+
+func (rc *readCache) list(family string) *cachedList {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	list, ok := rc.lists[family]
+	if !ok {
+		list = &cachedList{}
+		rc.lists[family] = list
+	}
+	return list
+}
+
+func (rc *readCache) invalidate(family string) {
+	if rc == nil {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	delete(rc.lists, family)
+}
+
+// loadList requests the list of family. A list that fails to load is left unloaded, so that the next read
+// tries again, and the failed read is answered by the single-item endpoint.
+func (c *Client) loadList(ctx context.Context, family string, list *cachedList) bool {
+	bytes, err := c.request(ctx, http.MethodGet, "/"+family, nil)
+	if err != nil && !IsNotFound(err) {
+		tflog.Debug(ctx, "unable to load list for read cache, reading the item individually", map[string]interface{}{
+			"endpoint": family,
+			"error":    err.Error(),
+		})
+		return false
+	}
+
+	// Robot answers an empty list with 404
+	list.items = make(map[string][]byte)
+	gjson.ParseBytes(bytes).ForEach(func(_, item gjson.Result) bool {
+		list.items[item.Get(cachedLists[family].key).String()] = []byte(item.Raw)
+		return true
+	})
+	list.loaded = true
+	return true
+}
+
+// cachedItem returns the raw JSON of one item of a list endpoint, in the same shape as the single-item
+// endpoint returns it. cached is false when the cache is disabled, the list could not be loaded or its items
+// lack fields, the caller then has to request the item itself. Items missing from a loaded list are reported
+// as not found.
+func (c *Client) cachedItem(ctx context.Context, family string, key string) (item []byte, cached bool, err error) {
+	if c.cache == nil {
+		return nil, false, nil
+	}
+
+	list := c.cache.list(family)
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if !list.loaded && !c.loadList(ctx, family, list) {
+		return nil, false, nil
+	}
+
+	item, ok := list.items[key]
+	if !ok {
+		return nil, true, &Error{
+			Status:  http.StatusNotFound,
+			Code:    "NOT_FOUND",
+			Message: fmt.Sprintf("%s %s not found", family, key),
+		}
+	}
+	if !cachedLists[family].complete {
+		return nil, false, nil
+	}
+	return item, true, nil
+}
+
+// getCached decodes the item key of a cached list into v, or requests path when the cache cannot answer.
+func (c *Client) getCached(ctx context.Context, family string, key string, path string, v interface{}) error {
+	item, cached, err := c.cachedItem(ctx, family, key)
+	if !cached {
+		return c.Do(ctx, http.MethodGet, path, nil, v)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(item, v)
+}
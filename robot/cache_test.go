@@ -0,0 +1,145 @@
+package robot_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+const cacheKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHZ4mA0FFDiw6HTBz9ah1qYmyuyRlYB4FeIZeaCZZ1g3 cache@example"
+
+// countRequests returns how many requests of method to path the fake received so far.
+func countRequests(fake *robottest.Server, method string, path string) int {
+	count := 0
+	for _, request := range fake.Requests() {
+		if request.Method == method && request.Path == path {
+			count++
+		}
+	}
+	return count
+}
+
+func TestReadCache(t *testing.T) {
+	fake := robottest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddServer(robot.Server{ServerNumber: 321, ServerIP: "123.123.123.123", Rescue: true})
+	key, err := fake.AddKey("cache", cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.Client(robot.WithReadCache(true))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		cached, err := client.Keys.Get(ctx, key.Fingerprint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cached.Name != "cache" || cached.Data != cacheKey {
+			t.Fatalf("incompletely decoded cached key: %+v", cached)
+		}
+	}
+	if lists, items := countRequests(fake, http.MethodGet, "/key"), countRequests(fake, http.MethodGet, "/key/"+key.Fingerprint); lists != 1 || items != 0 {
+		t.Fatalf("expected one list request and no item request, got %d and %d", lists, items)
+	}
+
+	// a mutating request drops the list of its endpoint family
+	if _, err := client.Keys.Rename(ctx, key.Fingerprint, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	renamed, err := client.Keys.Get(ctx, key.Fingerprint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "renamed" || countRequests(fake, http.MethodGet, "/key") != 2 {
+		t.Fatalf("expected the key list to be reloaded after the rename, got %+v", renamed)
+	}
+
+	if err := client.Keys.Delete(ctx, key.Fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Keys.Get(ctx, key.Fingerprint); !robot.IsNotFound(err) {
+		t.Fatalf("expected the deleted key to be missing from the reloaded list, got %v", err)
+	}
+
+	// the server and vSwitch lists lack fields, they only answer reads of objects that are gone
+	server, err := client.Servers.Get(ctx, 321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !server.Rescue || countRequests(fake, http.MethodGet, "/server/321") != 1 {
+		t.Fatalf("expected the server to be read individually, got %+v", server)
+	}
+	if _, err := client.Servers.Get(ctx, 999); !robot.IsNotFound(err) || countRequests(fake, http.MethodGet, "/server/999") != 0 {
+		t.Fatalf("expected the missing server to be answered from the list, got %v", err)
+	}
+	vSwitch, err := client.VSwitch.Create(ctx, robot.VSwitchRequest{Name: "cache", VLAN: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VSwitch.AddServers(ctx, vSwitch.ID, []int{321}); err != nil {
+		t.Fatal(err)
+	}
+	vSwitch, err = client.VSwitch.Get(ctx, vSwitch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vSwitch.Servers) != 1 {
+		t.Fatalf("expected the vSwitch to be read individually, got %+v", vSwitch)
+	}
+	if _, err := client.VSwitch.Get(ctx, vSwitch.ID+1); !robot.IsNotFound(err) || countRequests(fake, http.MethodGet, "/vswitch") != 1 {
+		t.Fatalf("expected the missing vSwitch to be answered from the list, got %v", err)
+	}
+	if lists := countRequests(fake, http.MethodGet, "/server"); lists != 1 {
+		t.Fatalf("expected one server list request, got %d", lists)
+	}
+}
+
+func TestReadCacheListFailure(t *testing.T) {
+	fake := robottest.NewServer()
+	t.Cleanup(fake.Close)
+	key, err := fake.AddKey("cache", cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.Client(robot.WithReadCache(true), robot.WithRetries(0, 0))
+	ctx := context.Background()
+
+	fake.InjectFault(robottest.Unavailable(http.MethodGet, "/key", 1))
+	if _, err := client.Keys.Get(ctx, key.Fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if lists, items := countRequests(fake, http.MethodGet, "/key"), countRequests(fake, http.MethodGet, "/key/"+key.Fingerprint); lists != 1 || items != 1 {
+		t.Fatalf("expected a failed list request and one item request, got %d and %d", lists, items)
+	}
+
+	// the next read tries the list again instead of reading individually for the rest of the run
+	for i := 0; i < 2; i++ {
+		if _, err := client.Keys.Get(ctx, key.Fingerprint); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lists, items := countRequests(fake, http.MethodGet, "/key"), countRequests(fake, http.MethodGet, "/key/"+key.Fingerprint); lists != 2 || items != 1 {
+		t.Fatalf("expected the list to be loaded again, got %d list and %d item requests", lists, items)
+	}
+}
+
+func TestReadCacheDisabled(t *testing.T) {
+	fake := robottest.NewServer()
+	t.Cleanup(fake.Close)
+	key, err := fake.AddKey("cache", cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.Client()
+
+	if _, err := client.Keys.Get(context.Background(), key.Fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if lists, items := countRequests(fake, http.MethodGet, "/key"), countRequests(fake, http.MethodGet, "/key/"+key.Fingerprint); lists != 0 || items != 1 {
+		t.Fatalf("expected no list request and one item request, got %d and %d", lists, items)
+	}
+}
@@ -0,0 +1,273 @@
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultBaseURL is the address of the Robot webservice.
+const DefaultBaseURL = "https://robot-ws.your-server.de"
+
+// Client talks to the Robot webservice. It is safe for concurrent use.
+type Client struct {
+	username   string
+	password   string
+	baseURL    string
+	maxRetries int
+	maxBackoff time.Duration
+	httpClient *http.Client
+	userAgent  string
+	headers    map[string]string
+	scheduler  *requestScheduler
+	cache      *readCache
+
+	logMetadataOnly bool
+	readOnly        bool
+	journal         *Journal
+
+	Servers      *ServerService
+	Boot         *BootService
+	Reset        *ResetService
+	Firewall     *FirewallService
+	VSwitch      *VSwitchService
+	Keys         *KeyService
+	IPs          *IPService
+	RDNS         *RDNSService
+	Failover     *FailoverService
+	StorageBoxes *StorageBoxService
+}
+
+// Option customizes a Client built by NewClient.
+type Option func(*Client)
+
+// WithBaseURL replaces DefaultBaseURL, e.g. to talk to a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithRetries sets how many times a failed request is retried and the upper bound of the wait between two attempts.
+func WithRetries(maxRetries int, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used to talk to the webservice, see NewHTTPClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeaders adds extra headers to every request, e.g. for an authenticating egress proxy.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// WithScheduler limits the number of requests in flight and the requests per hour of each endpoint family.
+func WithScheduler(maxConcurrentRequests int, budgets map[string]int) Option {
+	return func(c *Client) {
+		c.scheduler = newRequestScheduler(maxConcurrentRequests, budgets)
+	}
+}
+
+// WithReadCache enables or disables answering single-item reads from the list endpoints, see readCache.
+// A client built without it doesn't cache: the cache is only invalidated by the requests of the client itself,
+// so it suits short-lived programs like a Terraform run, where the provider enables it, not long-running ones.
+func WithReadCache(enabled bool) Option {
+	return func(c *Client) {
+		c.cache = nil
+		if enabled {
+			c.cache = newReadCache()
+		}
+	}
+}
+
+// WithLogMetadataOnly keeps request parameters and response bodies out of the debug logs.
+func WithLogMetadataOnly(metadataOnly bool) Option {
+	return func(c *Client) {
+		c.logMetadataOnly = metadataOnly
+	}
+}
+
+// NewClient returns a client authenticating with the given webservice credentials.
+func NewClient(username string, password string, opts ...Option) *Client {
+	c := &Client{
+		username:   username,
+		password:   password,
+		baseURL:    DefaultBaseURL,
+		maxRetries: DefaultMaxRetries,
+		maxBackoff: DefaultMaxBackoff,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  "hetzner-robot-go",
+		scheduler:  newRequestScheduler(DefaultMaxConcurrentRequests, nil),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.Servers = &ServerService{client: c}
+	c.Boot = &BootService{client: c}
+	c.Reset = &ResetService{client: c}
+	c.Firewall = &FirewallService{client: c}
+	c.VSwitch = &VSwitchService{client: c}
+	c.Keys = &KeyService{client: c}
+	c.IPs = &IPService{client: c}
+	c.RDNS = &RDNSService{client: c}
+	c.Failover = &FailoverService{client: c}
+	c.StorageBoxes = &StorageBoxService{client: c}
+	return c
+}
+
+// Do sends a request to path (e.g. "/server/321") and decodes the JSON response into v, unless v is nil.
+// Non-2xx responses are returned as *Error.
+func (c *Client) Do(ctx context.Context, method string, path string, data url.Values, v interface{}) error {
+	body, err := c.request(ctx, method, path, data)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unable to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *Client) request(ctx context.Context, method string, path string, data url.Values) ([]byte, error) {
+	if err := c.checkReadOnly(ctx, method, path); err != nil {
+		return nil, err
+	}
+
+	if method != http.MethodGet {
+		defer c.cache.invalidate(endpointFamily(path))
+	}
+
+	ctx = c.maskRequest(ctx, data)
+
+	statusCode, responseBytes, err := c.send(ctx, method, path, data)
+	if method != http.MethodGet {
+		c.journal.record(ctx, method, path, data, statusCode, responseBytes, err)
+	}
+	return responseBytes, err
+}
+
+// send performs a request, retrying it while shouldRetry allows, and returns the status of the last attempt.
+func (c *Client) send(ctx context.Context, method string, path string, data url.Values) (int, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		statusCode, responseBytes, err := c.doRequest(ctx, method, path, data)
+		if err == nil && statusCode >= 200 && statusCode < 300 {
+			return statusCode, responseBytes, nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("error sending request: %w", err)
+		} else {
+			err = newError(statusCode, responseBytes)
+		}
+
+		if attempt >= c.maxRetries || !shouldRetry(ctx, method, err) {
+			return statusCode, nil, err
+		}
+
+		wait := c.backoff(attempt)
+		if robotErr, ok := asError(err); ok && IsRateLimited(err) && robotErr.Interval > 0 {
+			if wait, ok = rateLimitWait(ctx, robotErr, c.maxBackoff); !ok {
+				return statusCode, nil, fmt.Errorf("rate limit of %d requests per %s exceeded, retry after %s: %w",
+					robotErr.MaxRequest, wait, time.Now().Add(wait).Format(time.RFC3339), err)
+			}
+		}
+		tflog.Warn(ctx, "retrying Hetzner webservice request", map[string]interface{}{
+			"path":    path,
+			"method":  method,
+			"error":   err.Error(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		})
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return statusCode, nil, err
+		}
+	}
+}
+
+func (c *Client) doRequest(ctx context.Context, method string, path string, data url.Values) (int, []byte, error) {
+	uri := c.baseURL + path
+
+	requestFields := map[string]interface{}{
+		"uri":    uri,
+		"method": method,
+	}
+	if caller := CallerFromContext(ctx); caller != "" {
+		requestFields["caller"] = caller
+	}
+	if !c.logMetadataOnly {
+		requestFields["data"] = formString(data)
+	}
+	tflog.Debug(ctx, "requesting Hetzner webservice", requestFields)
+
+	request, err := http.NewRequestWithContext(ctx, method, uri, strings.NewReader(data.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if data != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	request.Header.Set("User-Agent", c.userAgent)
+	for name, value := range c.headers {
+		request.Header.Set(name, value)
+	}
+
+	request.SetBasicAuth(c.username, c.password)
+
+	release, err := c.scheduler.acquire(ctx, endpointFamily(path))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer release()
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	defer response.Body.Close()
+
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	responseFields := map[string]interface{}{
+		"status": response.StatusCode,
+		"size":   len(responseBytes),
+	}
+	if !c.logMetadataOnly {
+		responseFields["body"] = string(responseBytes)
+	}
+	tflog.Debug(maskResponse(ctx, responseBytes), "got hetzner webservice response", responseFields)
+
+	return response.StatusCode, responseBytes, nil
+}
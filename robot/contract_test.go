@@ -0,0 +1,209 @@
+package robot_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+// The contract tests check the decoding of the client against the cassettes in testdata/documented, which are
+// hand-written from the response examples of the webservice documentation, not recorded: they hold the documented
+// format, not necessarily the one Robot sends. To record cassettes against the account of HETZNERROBOT_USERNAME /
+// HETZNERROBOT_PASSWORD into testdata/recorded, which replay then prefers:
+//
+//	ROBOT_RECORD=1 go test ./robot -run TestContract
+//
+// Recording activates and deactivates the rescue system of the first server of the account, creates and
+// cancels a vSwitch with VLAN 4091 and creates and deletes an SSH key.
+
+const contractKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHZ4mA0FFDiw6HTBz9ah1qYmyuyRlYB4FeIZeaCZZ1g3 contract@example"
+
+func contractClient(t *testing.T) *robot.Client {
+	t.Helper()
+
+	mode := robottest.Replay
+	username, password, baseURL := robottest.DefaultUsername, robottest.DefaultPassword, robot.DefaultBaseURL
+	cassette := filepath.Join("testdata", "recorded", t.Name()+".json")
+	if _, err := os.Stat(cassette); err != nil {
+		cassette = filepath.Join("testdata", "documented", t.Name()+".json")
+	}
+	if os.Getenv("ROBOT_RECORD") != "" {
+		mode = robottest.Record
+		cassette = filepath.Join("testdata", "recorded", t.Name()+".json")
+		username, password = os.Getenv("HETZNERROBOT_USERNAME"), os.Getenv("HETZNERROBOT_PASSWORD")
+		if url := os.Getenv("HETZNERROBOT_URL"); url != "" {
+			baseURL = url
+		}
+	}
+
+	recorder, err := robottest.NewRecorder(cassette, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := recorder.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return robot.NewClient(username, password,
+		robot.WithBaseURL(baseURL),
+		robot.WithHTTPClient(&http.Client{Transport: recorder}),
+		robot.WithRetries(0, 0),
+	)
+}
+
+// contractServer returns the first server of the account.
+func contractServer(t *testing.T, client *robot.Client) robot.Server {
+	t.Helper()
+
+	servers, err := client.Servers.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) == 0 {
+		t.Fatal("expected at least one server")
+	}
+	return servers[0]
+}
+
+func TestContractServers(t *testing.T) {
+	client := contractClient(t)
+	ctx := context.Background()
+
+	listed := contractServer(t, client)
+	if listed.ServerNumber == 0 || listed.ServerIP == "" || listed.Product == "" || listed.DataCenter == "" || listed.Status == "" {
+		t.Fatalf("incompletely decoded server list entry: %+v", listed)
+	}
+
+	server, err := client.Servers.Get(ctx, listed.ServerNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.ServerNumber != listed.ServerNumber || server.ServerIP != listed.ServerIP || len(server.IPs) == 0 {
+		t.Fatalf("incompletely decoded server: %+v", server)
+	}
+	if !server.Reset {
+		t.Fatalf("expected the server to support resets: %+v", server)
+	}
+
+	reset, err := client.Reset.Get(ctx, server.ServerNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reset.Type.Contains(robot.ResetSoftware) || !reset.Type.Contains(robot.ResetHardware) {
+		t.Fatalf("unexpected reset types: %v", reset.Type)
+	}
+}
+
+func TestContractBoot(t *testing.T) {
+	client := contractClient(t)
+	ctx := context.Background()
+	server := contractServer(t, client)
+
+	boot, err := client.Boot.Get(ctx, server.ServerNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if boot.Rescue == nil || boot.Linux == nil {
+		t.Fatalf("expected the rescue and linux profiles: %+v", boot)
+	}
+	if boot.Rescue.Active || !boot.Rescue.OS.Contains("linux") || len(boot.Linux.Dist) == 0 || len(boot.Linux.Lang) == 0 {
+		t.Fatalf("unexpected inactive profiles: %+v / %+v", boot.Rescue, boot.Linux)
+	}
+
+	rescue, err := client.Boot.ActivateRescue(ctx, server.ServerNumber, robot.RescueRequest{OS: "linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rescue.Active || rescue.OS.First() != "linux" || rescue.Password == "" || rescue.ServerNumber != server.ServerNumber {
+		t.Fatalf("unexpected active rescue system: %+v", rescue)
+	}
+
+	if err := client.Boot.Deactivate(ctx, server.ServerNumber, robot.BootProfileRescue); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContractFirewall(t *testing.T) {
+	client := contractClient(t)
+	server := contractServer(t, client)
+
+	firewall, err := client.Firewall.Get(context.Background(), server.ServerIP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firewall.ServerNumber != server.ServerNumber || firewall.Status == "" || firewall.Port == "" {
+		t.Fatalf("incompletely decoded firewall: %+v", firewall)
+	}
+	for _, rule := range append(firewall.Rules.Input, firewall.Rules.Output...) {
+		if rule.Action == "" {
+			t.Fatalf("incompletely decoded firewall rule: %+v", rule)
+		}
+	}
+}
+
+func TestContractVSwitch(t *testing.T) {
+	client := contractClient(t)
+	ctx := context.Background()
+
+	vSwitch, err := client.VSwitch.Create(ctx, robot.VSwitchRequest{Name: "contract-test", VLAN: 4091})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vSwitch.ID == 0 || vSwitch.Name != "contract-test" || vSwitch.VLAN != 4091 || vSwitch.Cancelled {
+		t.Fatalf("unexpected vSwitch: %+v", vSwitch)
+	}
+
+	if err := client.VSwitch.Update(ctx, vSwitch.ID, robot.VSwitchRequest{Name: "contract-test-renamed", VLAN: 4091}); err != nil {
+		t.Fatal(err)
+	}
+	vSwitch, err = client.VSwitch.Get(ctx, vSwitch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vSwitch.Name != "contract-test-renamed" || vSwitch.Servers == nil || vSwitch.Subnets == nil || vSwitch.CloudNetworks == nil {
+		t.Fatalf("unexpected vSwitch: %+v", vSwitch)
+	}
+
+	if err := client.VSwitch.Cancel(ctx, vSwitch.ID, "now"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContractKeys(t *testing.T) {
+	client := contractClient(t)
+	ctx := context.Background()
+
+	key, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "contract-test", Data: contractKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := robot.KeyFingerprint(contractKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Fingerprint != fingerprint || key.Type != "ED25519" || key.Size != 256 || key.CreatedAt == "" {
+		t.Fatalf("unexpected key: %+v", key)
+	}
+
+	if _, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "contract-test", Data: contractKey}); !robot.HasErrorCode(err, "KEY_ALREADY_EXISTS") {
+		t.Fatalf("expected KEY_ALREADY_EXISTS, got %v", err)
+	}
+
+	if key, err := client.Keys.Rename(ctx, fingerprint, "contract-test-renamed"); err != nil || key.Name != "contract-test-renamed" {
+		t.Fatalf("unexpected renamed key: %+v, %v", key, err)
+	}
+
+	if err := client.Keys.Delete(ctx, fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Keys.Get(ctx, fingerprint); !robot.IsNotFound(err) {
+		t.Fatalf("expected the key to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+// Package robot is a client for the Hetzner Robot webservice
+// (https://robot.your-server.de/doc/webservice/en.html).
+//
+// A Client bundles typed services for the webservice endpoints:
+//
+//	client := robot.NewClient(username, password)
+//	server, err := client.Servers.Get(ctx, 321)
+//	if robot.IsNotFound(err) {
+//		...
+//	}
+//
+// Failed requests are retried with backoff when Robot reports a rate limit or a transient failure,
+// and requests are queued through a scheduler that caps concurrency and spreads the requests of each
+// endpoint family over the hour. Errors reported by the webservice are returned as *Error.
+//
+// Requests and responses are logged through terraform-plugin-log, which is a no-op outside of a
+// Terraform provider.
+package robot
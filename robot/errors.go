@@ -0,0 +1,103 @@
+package robot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error is the error reported by the Robot webservice, decoded from its
+// {"error": {"status", "code", "message", ...}} response body.
+type Error struct {
+	Status  int      `json:"status"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Missing []string `json:"missing"`
+	Invalid []string `json:"invalid"`
+
+	// only set for RATE_LIMIT_EXCEEDED
+	MaxRequest int `json:"max_request"`
+	Interval   int `json:"interval"`
+}
+
+type errorResponse struct {
+	Error Error `json:"error"`
+}
+
+// newError builds the error for an unexpected response. Bodies that are not
+// Robot error documents (e.g. the plain text 401 page) are kept as the message.
+func newError(statusCode int, body []byte) *Error {
+	response := errorResponse{}
+	if err := json.Unmarshal(body, &response); err != nil || response.Error.Code == "" {
+		return &Error{
+			Status:  statusCode,
+			Code:    strings.ToUpper(strings.ReplaceAll(http.StatusText(statusCode), " ", "_")),
+			Message: strings.TrimSpace(string(body)),
+		}
+	}
+
+	response.Error.Status = statusCode
+	return &response.Error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("hetzner webservice response status %d: %s", e.Status, e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if len(e.Missing) > 0 {
+		msg += fmt.Sprintf(" (missing: %s)", strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		msg += fmt.Sprintf(" (invalid: %s)", strings.Join(e.Invalid, ", "))
+	}
+	return msg
+}
+
+func asError(err error) (*Error, bool) {
+	var robotErr *Error
+	if errors.As(err, &robotErr) {
+		return robotErr, true
+	}
+	return nil, false
+}
+
+// HasErrorCode reports whether err is a webservice error with the given Robot error code (e.g. BOOT_ALREADY_ENABLED).
+func HasErrorCode(err error, code string) bool {
+	robotErr, ok := asError(err)
+	return ok && robotErr.Code == code
+}
+
+// IsNotFound reports whether the requested object does not exist (anymore).
+func IsNotFound(err error) bool {
+	robotErr, ok := asError(err)
+	return ok && robotErr.Status == http.StatusNotFound
+}
+
+// IsConflict reports whether the request conflicts with the current state of the object,
+// e.g. an already enabled boot profile or a firewall update still in process.
+func IsConflict(err error) bool {
+	robotErr, ok := asError(err)
+	return ok && robotErr.Status == http.StatusConflict
+}
+
+// IsRateLimited reports whether Robot refused the request because the hourly limit of the endpoint is exhausted.
+func IsRateLimited(err error) bool {
+	robotErr, ok := asError(err)
+	return ok && robotErr.Status == http.StatusForbidden && robotErr.Code == "RATE_LIMIT_EXCEEDED"
+}
+
+// IsUnauthorized reports whether Robot rejected the credentials.
+func IsUnauthorized(err error) bool {
+	robotErr, ok := asError(err)
+	return ok && robotErr.Status == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether the webservice user may not access the endpoint. Rate limits, which Robot
+// reports with the same status, don't count.
+func IsForbidden(err error) bool {
+	robotErr, ok := asError(err)
+	return ok && robotErr.Status == http.StatusForbidden && robotErr.Code != "RATE_LIMIT_EXCEEDED"
+}
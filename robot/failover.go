@@ -0,0 +1,69 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#failover
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type Failover struct {
+	IP             string `json:"ip"`
+	Netmask        string `json:"netmask"`
+	ServerIP       string `json:"server_ip"`
+	ServerIPv6Net  string `json:"server_ipv6_net"`
+	ServerNumber   int    `json:"server_number"`
+	ActiveServerIP string `json:"active_server_ip"`
+}
+
+type failoverResponse struct {
+	Failover Failover `json:"failover"`
+}
+
+// FailoverService handles the /failover endpoints.
+type FailoverService struct {
+	client *Client
+}
+
+func (s *FailoverService) List(ctx context.Context) ([]Failover, error) {
+	var response []failoverResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/failover", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	failovers := make([]Failover, 0, len(response))
+	for _, item := range response {
+		failovers = append(failovers, item.Failover)
+	}
+	return failovers, nil
+}
+
+func (s *FailoverService) Get(ctx context.Context, ip string) (*Failover, error) {
+	response := failoverResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/failover/%s", ip), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Failover, nil
+}
+
+// Route switches the failover IP to the server with the given main IP.
+func (s *FailoverService) Route(ctx context.Context, ip string, activeServerIP string) (*Failover, error) {
+	data := url.Values{}
+	data.Set("active_server_ip", activeServerIP)
+
+	response := failoverResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/failover/%s", ip), data, &response); err != nil {
+		return nil, err
+	}
+	return &response.Failover, nil
+}
+
+// Unroute deletes the routing of the failover IP.
+func (s *FailoverService) Unroute(ctx context.Context, ip string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/failover/%s", ip), nil, nil)
+}
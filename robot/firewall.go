@@ -0,0 +1,104 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#firewall
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type FirewallRule struct {
+	IPVersion string `json:"ip_version"`
+	Name      string `json:"name"`
+	DstIP     string `json:"dst_ip"`
+	DstPort   string `json:"dst_port"`
+	SrcIP     string `json:"src_ip"`
+	SrcPort   string `json:"src_port"`
+	Protocol  string `json:"protocol"`
+	TCPFlags  string `json:"tcp_flags"`
+	Action    string `json:"action"`
+}
+
+type FirewallRules struct {
+	Input  []FirewallRule `json:"input"`
+	Output []FirewallRule `json:"output"`
+}
+
+type Firewall struct {
+	ServerIP     string        `json:"server_ip"`
+	ServerNumber int           `json:"server_number"`
+	Status       string        `json:"status"` // active, disabled or in process
+	WhitelistHOS bool          `json:"whitelist_hos"`
+	Port         string        `json:"port"`
+	Rules        FirewallRules `json:"rules"`
+}
+
+type firewallResponse struct {
+	Firewall Firewall `json:"firewall"`
+}
+
+type FirewallRequest struct {
+	Status       string // active or disabled
+	WhitelistHOS bool
+	Rules        FirewallRules
+}
+
+func encodeFirewallRules(data url.Values, direction string, rules []FirewallRule) {
+	for idx, rule := range rules {
+		set := func(field string, value string) {
+			setIfNotEmpty(data, fmt.Sprintf("rules[%s][%d][%s]", direction, idx, field), value)
+		}
+		set("ip_version", rule.IPVersion)
+		set("name", rule.Name)
+		set("dst_ip", rule.DstIP)
+		set("dst_port", rule.DstPort)
+		set("src_ip", rule.SrcIP)
+		set("src_port", rule.SrcPort)
+		set("protocol", rule.Protocol)
+		set("tcp_flags", rule.TCPFlags)
+		set("action", rule.Action)
+	}
+}
+
+func (r FirewallRequest) values() url.Values {
+	data := url.Values{}
+	data.Set("status", r.Status)
+	data.Set("whitelist_hos", boolString(r.WhitelistHOS))
+	encodeFirewallRules(data, "input", r.Rules.Input)
+	encodeFirewallRules(data, "output", r.Rules.Output)
+	return data
+}
+
+// FirewallService handles the /firewall endpoints. Firewalls are addressed by server IP or server number.
+type FirewallService struct {
+	client *Client
+}
+
+func (s *FirewallService) Get(ctx context.Context, id string) (*Firewall, error) {
+	response := firewallResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/firewall/%s", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Firewall, nil
+}
+
+// Update replaces the firewall configuration. Robot applies it asynchronously, the returned status is
+// "in process" until it is done.
+func (s *FirewallService) Update(ctx context.Context, id string, request FirewallRequest) (*Firewall, error) {
+	response := firewallResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/firewall/%s", id), request.values(), &response); err != nil {
+		return nil, err
+	}
+	return &response.Firewall, nil
+}
+
+// Delete removes all rules and disables the firewall.
+func (s *FirewallService) Delete(ctx context.Context, id string) (*Firewall, error) {
+	response := firewallResponse{}
+	if err := s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/firewall/%s", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Firewall, nil
+}
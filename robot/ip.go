@@ -0,0 +1,90 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#ip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type IP struct {
+	IP              string `json:"ip"`
+	ServerIP        string `json:"server_ip"`
+	ServerNumber    int    `json:"server_number"`
+	Locked          bool   `json:"locked"`
+	SeparateMAC     string `json:"separate_mac"`
+	TrafficWarnings bool   `json:"traffic_warnings"`
+	TrafficHourly   int    `json:"traffic_hourly"`
+	TrafficDaily    int    `json:"traffic_daily"`
+	TrafficMonthly  int    `json:"traffic_monthly"`
+	Gateway         string `json:"gateway"`
+	Mask            int    `json:"mask"`
+	Broadcast       string `json:"broadcast"`
+}
+
+type ipResponse struct {
+	IP IP `json:"ip"`
+}
+
+// IPTrafficWarningsRequest configures the traffic warnings of an IP, the limits are in MB.
+type IPTrafficWarningsRequest struct {
+	TrafficWarnings bool
+	TrafficHourly   int
+	TrafficDaily    int
+	TrafficMonthly  int
+}
+
+func (r IPTrafficWarningsRequest) values() url.Values {
+	data := url.Values{}
+	data.Set("traffic_warnings", boolString(r.TrafficWarnings))
+	if r.TrafficHourly > 0 {
+		data.Set("traffic_hourly", strconv.Itoa(r.TrafficHourly))
+	}
+	if r.TrafficDaily > 0 {
+		data.Set("traffic_daily", strconv.Itoa(r.TrafficDaily))
+	}
+	if r.TrafficMonthly > 0 {
+		data.Set("traffic_monthly", strconv.Itoa(r.TrafficMonthly))
+	}
+	return data
+}
+
+// IPService handles the /ip endpoints.
+type IPService struct {
+	client *Client
+}
+
+func (s *IPService) List(ctx context.Context) ([]IP, error) {
+	var response []ipResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/ip", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ips := make([]IP, 0, len(response))
+	for _, item := range response {
+		ips = append(ips, item.IP)
+	}
+	return ips, nil
+}
+
+func (s *IPService) Get(ctx context.Context, ip string) (*IP, error) {
+	response := ipResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/ip/%s", ip), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.IP, nil
+}
+
+func (s *IPService) UpdateTrafficWarnings(ctx context.Context, ip string, request IPTrafficWarningsRequest) (*IP, error) {
+	response := ipResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/ip/%s", ip), request.values(), &response); err != nil {
+		return nil, err
+	}
+	return &response.IP, nil
+}
@@ -0,0 +1,132 @@
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tidwall/gjson"
+)
+
+// createdIDs maps the endpoints creating objects to the JSON path of the ID Robot assigns in the response.
+var createdIDs = map[string]string{
+	"/key":     "key.fingerprint",
+	"/vswitch": "id",
+}
+
+// JournalEntry is the record of one mutating webservice request.
+type JournalEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Method    string    `json:"method"`
+	Endpoint  string    `json:"endpoint"`
+	// Resource is the label of the caller, see ContextWithCaller. The provider labels requests with the resource
+	// type and ID, as Terraform doesn't pass resource addresses to providers, and with the type only on create.
+	Resource string `json:"resource,omitempty"`
+	// CreatedID is the ID Robot assigned to the object a successful create request made.
+	CreatedID string              `json:"created_id,omitempty"`
+	Params    map[string][]string `json:"params,omitempty"`
+	Status    int                 `json:"status"`
+	ErrorCode string              `json:"error_code,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// Journal appends a JSON line per mutating (POST, PUT, DELETE) request. Reads are not recorded.
+type Journal struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJournal returns a journal writing to w.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{w: w}
+}
+
+// OpenJournal returns a journal appending to the file at path, creating it if needed.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit journal: %w", err)
+	}
+	return NewJournal(f), nil
+}
+
+// WithJournal records every mutating request of the client in j.
+func WithJournal(j *Journal) Option {
+	return func(c *Client) {
+		c.journal = j
+	}
+}
+
+// Record appends entry to the journal.
+func (j *Journal) Record(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// a single write per line, so that concurrent runs appending to the same file don't interleave
+	_, err = j.w.Write(append(line, '\n'))
+	return err
+}
+
+func (j *Journal) record(ctx context.Context, method string, path string, data url.Values, statusCode int, body []byte, err error) {
+	if j == nil {
+		return
+	}
+
+	entry := JournalEntry{
+		Timestamp: time.Now().UTC(),
+		Method:    method,
+		Endpoint:  path,
+		Resource:  CallerFromContext(ctx),
+		Params:    RedactParams(data),
+		Status:    statusCode,
+	}
+	if idPath, ok := createdIDs[path]; ok && err == nil && method == http.MethodPost {
+		entry.CreatedID = gjson.GetBytes(body, idPath).String()
+	}
+	if err != nil {
+		if robotErr, ok := asError(err); ok {
+			entry.ErrorCode = robotErr.Code
+		} else {
+			entry.Error = err.Error()
+		}
+	}
+
+	if err := j.Record(entry); err != nil {
+		tflog.Error(ctx, "unable to write audit journal", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// RedactParams returns a copy of a request form with the values of sensitive parameters replaced, as written
+// to the audit journal.
+func RedactParams(data url.Values) map[string][]string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	params := make(map[string][]string, len(data))
+	for name, values := range data {
+		if !IsSensitiveField(name) {
+			params[name] = values
+			continue
+		}
+		redacted := make([]string, len(values))
+		for i := range values {
+			redacted[i] = "***"
+		}
+		params[name] = redacted
+	}
+	return params
+}
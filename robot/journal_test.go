@@ -0,0 +1,98 @@
+package robot_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+func TestJournal(t *testing.T) {
+	fake := robottest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddServer(robot.Server{ServerNumber: 321, ServerIP: "123.123.123.123", Rescue: true})
+
+	var buf bytes.Buffer
+	client := fake.Client(robot.WithJournal(robot.NewJournal(&buf)))
+	ctx := robot.ContextWithCaller(context.Background(), "hetzner-robot_ssh_key")
+
+	key, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "journal", Data: cacheKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "again", Data: cacheKey}); !robot.HasErrorCode(err, "KEY_ALREADY_EXISTS") {
+		t.Fatalf("expected KEY_ALREADY_EXISTS, got %v", err)
+	}
+	// reads are not recorded
+	if _, err := client.Keys.Get(ctx, key.Fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Boot.ActivateRescue(context.Background(), 321, robot.RescueRequest{OS: "linux", AuthorizedKeys: []string{key.Fingerprint}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []robot.JournalEntry
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var entry robot.JournalEntry
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+
+	created := entries[0]
+	if created.Method != http.MethodPost || created.Endpoint != "/key" || created.Resource != "hetzner-robot_ssh_key" ||
+		created.CreatedID != key.Fingerprint || created.Status != http.StatusCreated || created.ErrorCode != "" {
+		t.Fatalf("unexpected create entry: %+v", created)
+	}
+	if !reflect.DeepEqual(created.Params, map[string][]string{"name": {"journal"}, "data": {cacheKey}}) {
+		t.Fatalf("unexpected create parameters: %v", created.Params)
+	}
+
+	failed := entries[1]
+	if failed.CreatedID != "" || failed.ErrorCode != "KEY_ALREADY_EXISTS" || failed.Status != http.StatusConflict {
+		t.Fatalf("unexpected failed create entry: %+v", failed)
+	}
+
+	rescue := entries[2]
+	if rescue.Endpoint != "/boot/321/rescue" || rescue.Resource != "" || rescue.CreatedID != "" {
+		t.Fatalf("unexpected rescue entry: %+v", rescue)
+	}
+	if !reflect.DeepEqual(rescue.Params, map[string][]string{"os": {"linux"}, "authorized_key": {"***"}}) {
+		t.Fatalf("expected the authorized keys to be redacted, got %v", rescue.Params)
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	if params := robot.RedactParams(nil); params != nil {
+		t.Fatalf("expected no parameters, got %v", params)
+	}
+
+	data := url.Values{
+		"password":         {"secret"},
+		"authorized_key[]": {"a", "b"},
+		"name":             {"visible"},
+	}
+	params := robot.RedactParams(data)
+	expected := map[string][]string{
+		"password":         {"***"},
+		"authorized_key[]": {"***", "***"},
+		"name":             {"visible"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("expected %v, got %v", expected, params)
+	}
+	if data.Get("password") != "secret" {
+		t.Fatal("expected the form to be left alone")
+	}
+}
@@ -0,0 +1,158 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#ssh-keys
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type Key struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Type        string `json:"type"`
+	Size        int    `json:"size"`
+	Data        string `json:"data"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type keyResponse struct {
+	Key Key `json:"key"`
+}
+
+// KeyList is a list of keys as embedded in other responses, e.g. the authorized and host keys of a boot profile.
+type KeyList []Key
+
+func (l *KeyList) UnmarshalJSON(data []byte) error {
+	var items []keyResponse
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	list := make(KeyList, 0, len(items))
+	for _, item := range items {
+		list = append(list, item.Key)
+	}
+	*l = list
+	return nil
+}
+
+// Fingerprints returns the fingerprints of the keys.
+func (l KeyList) Fingerprints() []string {
+	fingerprints := make([]string, 0, len(l))
+	for _, key := range l {
+		fingerprints = append(fingerprints, key.Fingerprint)
+	}
+	return fingerprints
+}
+
+type KeyCreateRequest struct {
+	Name string
+	// Data is the public key in OpenSSH or SSH2 format.
+	Data string
+}
+
+// ParsePublicKey parses a public key in one of the formats Robot accepts: OpenSSH ("ssh-ed25519 AAAA... comment")
+// or SSH2 (RFC 4716).
+func ParsePublicKey(data string) (ssh.PublicKey, error) {
+	data = strings.TrimSpace(data)
+	if !strings.HasPrefix(data, "---- BEGIN SSH2 PUBLIC KEY ----") {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(data))
+		return key, err
+	}
+
+	var encoded strings.Builder
+	header := false
+	for _, line := range strings.Split(data, "\n")[1:] {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "---- END SSH2 PUBLIC KEY ----") {
+			blob, err := base64.StdEncoding.DecodeString(encoded.String())
+			if err != nil {
+				return nil, err
+			}
+			return ssh.ParsePublicKey(blob)
+		}
+		// headers ("Comment: ...") may continue over several lines ending with a backslash
+		if header || strings.Contains(line, ":") {
+			header = strings.HasSuffix(line, "\\")
+			continue
+		}
+		encoded.WriteString(line)
+	}
+	return nil, errors.New("ssh: missing end of SSH2 public key")
+}
+
+// KeyFingerprint returns the fingerprint Robot identifies a public key by, the MD5 hash of the key
+// (e.g. "56:29:99:a4:5d:ed:ac:95:c1:f5:88:82:90:5d:dd:10").
+func KeyFingerprint(data string) (string, error) {
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintLegacyMD5(key), nil
+}
+
+// KeyService handles the /key endpoints.
+type KeyService struct {
+	client *Client
+}
+
+// List returns all keys of the account.
+func (s *KeyService) List(ctx context.Context) ([]Key, error) {
+	var response []keyResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/key", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(response))
+	for _, item := range response {
+		keys = append(keys, item.Key)
+	}
+	return keys, nil
+}
+
+// Get returns a key by fingerprint. With the read cache enabled it is answered from the key list.
+func (s *KeyService) Get(ctx context.Context, fingerprint string) (*Key, error) {
+	response := keyResponse{}
+	if err := s.client.getCached(ctx, "key", fingerprint, fmt.Sprintf("/key/%s", fingerprint), &response); err != nil {
+		return nil, err
+	}
+	return &response.Key, nil
+}
+
+func (s *KeyService) Create(ctx context.Context, request KeyCreateRequest) (*Key, error) {
+	data := url.Values{}
+	data.Set("name", request.Name)
+	data.Set("data", request.Data)
+
+	response := keyResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, "/key", data, &response); err != nil {
+		return nil, err
+	}
+	return &response.Key, nil
+}
+
+func (s *KeyService) Rename(ctx context.Context, fingerprint string, name string) (*Key, error) {
+	data := url.Values{}
+	data.Set("name", name)
+
+	response := keyResponse{}
+	if err := s.client.Do(ctx, http.MethodPut, fmt.Sprintf("/key/%s", fingerprint), data, &response); err != nil {
+		return nil, err
+	}
+	return &response.Key, nil
+}
+
+func (s *KeyService) Delete(ctx context.Context, fingerprint string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/key/%s", fingerprint), nil, nil)
+}
@@ -0,0 +1,64 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#reverse-dns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type RDNS struct {
+	IP  string `json:"ip"`
+	PTR string `json:"ptr"`
+}
+
+type rdnsResponse struct {
+	RDNS RDNS `json:"rdns"`
+}
+
+// RDNSService handles the /rdns endpoints.
+type RDNSService struct {
+	client *Client
+}
+
+func (s *RDNSService) List(ctx context.Context) ([]RDNS, error) {
+	var response []rdnsResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/rdns", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]RDNS, 0, len(response))
+	for _, item := range response {
+		entries = append(entries, item.RDNS)
+	}
+	return entries, nil
+}
+
+func (s *RDNSService) Get(ctx context.Context, ip string) (*RDNS, error) {
+	response := rdnsResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/rdns/%s", ip), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.RDNS, nil
+}
+
+// Set creates or replaces the PTR record of an IP.
+func (s *RDNSService) Set(ctx context.Context, ip string, ptr string) (*RDNS, error) {
+	data := url.Values{}
+	data.Set("ptr", ptr)
+
+	response := rdnsResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/rdns/%s", ip), data, &response); err != nil {
+		return nil, err
+	}
+	return &response.RDNS, nil
+}
+
+func (s *RDNSService) Delete(ctx context.Context, ip string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/rdns/%s", ip), nil, nil)
+}
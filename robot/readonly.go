@@ -0,0 +1,61 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type callerKey struct{}
+
+// ContextWithCaller labels the requests sent with ctx, e.g. with the Terraform resource they are sent for.
+// The label shows up in logs and in the errors of read-only clients.
+func ContextWithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the label set by ContextWithCaller, or "".
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// ReadOnlyError is returned by a read-only client for every request that could change something.
+type ReadOnlyError struct {
+	Method string
+	Path   string
+	Caller string
+}
+
+func (e *ReadOnlyError) Error() string {
+	msg := fmt.Sprintf("refusing to send %s %s: the client is read-only", e.Method, e.Path)
+	if e.Caller != "" {
+		msg = e.Caller + ": " + msg
+	}
+	return msg
+}
+
+// IsReadOnly reports whether err was returned because the client is read-only.
+func IsReadOnly(err error) bool {
+	var readOnlyErr *ReadOnlyError
+	return errors.As(err, &readOnlyErr)
+}
+
+// WithReadOnly makes the client refuse every request but GET, so that it can never change a server.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Client) {
+		c.readOnly = readOnly
+	}
+}
+
+func (c *Client) checkReadOnly(ctx context.Context, method string, path string) error {
+	if !c.readOnly || method == http.MethodGet || method == http.MethodHead {
+		return nil
+	}
+	return &ReadOnlyError{
+		Method: method,
+		Path:   path,
+		Caller: CallerFromContext(ctx),
+	}
+}
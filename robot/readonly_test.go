@@ -0,0 +1,42 @@
+package robot_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot/robottest"
+)
+
+func TestReadOnly(t *testing.T) {
+	fake := robottest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddServer(robot.Server{ServerNumber: 321, ServerIP: "123.123.123.123", Rescue: true})
+	c := fake.Client(robot.WithReadOnly(true))
+	ctx := robot.ContextWithCaller(context.Background(), "hetzner-robot_boot 321")
+
+	if _, err := c.Boot.Get(ctx, 321); err != nil {
+		t.Fatalf("expected reads to pass, got %v", err)
+	}
+
+	_, err := c.Boot.ActivateRescue(ctx, 321, robot.RescueRequest{OS: "linux"})
+	if !robot.IsReadOnly(err) {
+		t.Fatalf("expected a read-only error, got %v", err)
+	}
+	if want := "hetzner-robot_boot 321: refusing to send POST /boot/321/rescue: the client is read-only"; err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+	if err := c.Boot.Deactivate(context.Background(), 321, robot.BootProfileRescue); !robot.IsReadOnly(err) {
+		t.Fatalf("expected a read-only error, got %v", err)
+	}
+	// the refused requests never reach Robot, and aren't retried
+	if count := countRequests(fake, http.MethodPost, "/boot/321/rescue") + countRequests(fake, http.MethodDelete, "/boot/321/rescue"); count != 0 {
+		t.Fatalf("expected no mutating request, got %d", count)
+	}
+
+	// the same requests pass without read-only
+	if _, err := fake.Client(robot.WithReadOnly(false)).Boot.ActivateRescue(ctx, 321, robot.RescueRequest{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+}
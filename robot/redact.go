@@ -0,0 +1,97 @@
+package robot
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tidwall/gjson"
+)
+
+// sensitiveFields are the request parameters and response attributes whose values must not show up in
+// debug logs: rescue, installation and storage box (subaccount) passwords and authorized keys.
+var sensitiveFields = []string{"password", "authorized_key"}
+
+// IsSensitiveField reports whether a request parameter or response attribute holds a secret, see sensitiveFields.
+func IsSensitiveField(name string) bool {
+	for _, field := range sensitiveFields {
+		if name == field || strings.HasPrefix(name, field+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecrets returns a context whose logger replaces the given values with asterisks in messages and fields.
+func maskSecrets(ctx context.Context, secrets ...string) context.Context {
+	values := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		// masking an empty string would mask every position of every field
+		if secret != "" {
+			values = append(values, secret)
+		}
+	}
+	if len(values) == 0 {
+		return ctx
+	}
+
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, values...)
+	return tflog.MaskMessageStrings(ctx, values...)
+}
+
+// maskRequest masks the client credentials and the sensitive parameters of a request form.
+func (c *Client) maskRequest(ctx context.Context, data url.Values) context.Context {
+	secrets := []string{c.username, c.password}
+	for name, values := range data {
+		if IsSensitiveField(name) {
+			secrets = append(secrets, values...)
+		}
+	}
+	return maskSecrets(ctx, secrets...)
+}
+
+// maskResponse masks the sensitive attributes found anywhere in a JSON response body.
+func maskResponse(ctx context.Context, body []byte) context.Context {
+	return maskSecrets(ctx, sensitiveValues(gjson.ParseBytes(body), false)...)
+}
+
+func sensitiveValues(value gjson.Result, sensitive bool) []string {
+	if !value.IsObject() && !value.IsArray() {
+		if sensitive {
+			return []string{value.String()}
+		}
+		return nil
+	}
+
+	var values []string
+	value.ForEach(func(key, item gjson.Result) bool {
+		values = append(values, sensitiveValues(item, sensitive || IsSensitiveField(key.String()))...)
+		return true
+	})
+	return values
+}
+
+// formString renders a request form for the logs. Unlike url.Values.Encode it keeps values unescaped,
+// so that they match the masked strings.
+func formString(data url.Values) string {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		for _, value := range data[name] {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(name)
+			sb.WriteByte('=')
+			sb.WriteString(value)
+		}
+	}
+	return sb.String()
+}
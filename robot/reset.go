@@ -0,0 +1,72 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#reset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Reset types.
+const (
+	ResetSoftware = "sw"    // CTRL+ALT+DEL
+	ResetHardware = "hw"    // hardware reset
+	ResetPower    = "power" // press the power button
+	ResetManual   = "man"   // manual reset by a technician
+)
+
+type Reset struct {
+	ServerIP        string     `json:"server_ip"`
+	ServerIPv6Net   string     `json:"server_ipv6_net"`
+	ServerNumber    int        `json:"server_number"`
+	Type            StringList `json:"type"` // available types, or the executed one
+	OperatingStatus string     `json:"operating_status"`
+}
+
+type resetResponse struct {
+	Reset Reset `json:"reset"`
+}
+
+// ResetService handles the /reset endpoints.
+type ResetService struct {
+	client *Client
+}
+
+func (s *ResetService) List(ctx context.Context) ([]Reset, error) {
+	var response []resetResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/reset", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resets := make([]Reset, 0, len(response))
+	for _, item := range response {
+		resets = append(resets, item.Reset)
+	}
+	return resets, nil
+}
+
+// Get returns the reset types available for a server.
+func (s *ResetService) Get(ctx context.Context, serverNumber int) (*Reset, error) {
+	response := resetResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/reset/%d", serverNumber), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Reset, nil
+}
+
+// Execute resets a server with one of the Reset types.
+func (s *ResetService) Execute(ctx context.Context, serverNumber int, resetType string) (*Reset, error) {
+	data := url.Values{}
+	data.Set("type", resetType)
+
+	response := resetResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/reset/%d", serverNumber), data, &response); err != nil {
+		return nil, err
+	}
+	return &response.Reset, nil
+}
@@ -0,0 +1,119 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries of a client built without WithRetries.
+	DefaultMaxRetries = 5
+	// DefaultMaxBackoff is the longest wait between two attempts of a client built without WithRetries.
+	DefaultMaxBackoff = 30 * time.Second
+
+	minBackoff = time.Second
+)
+
+// isIdempotent reports whether sending the request twice has the same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry decides whether a failed attempt may be sent again.
+// Rate-limited requests and requests that never reached Robot are always safe to repeat,
+// other transient failures are only retried for idempotent methods.
+func shouldRetry(ctx context.Context, method string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if IsRateLimited(err) {
+		return true
+	}
+
+	robotErr, ok := asError(err)
+	if !ok {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return isIdempotent(method)
+	}
+
+	switch robotErr.Status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	}
+	return false
+}
+
+// IsAmbiguous reports whether a failed request may nevertheless have been carried out by Robot: the
+// connection dropped or timed out after the request was sent, or Robot or a gateway failed while handling it.
+// Creates failing this way should look for the object before reporting the error.
+func IsAmbiguous(err error) bool {
+	if err == nil || IsReadOnly(err) {
+		return false
+	}
+
+	robotErr, ok := asError(err)
+	if !ok {
+		var opErr *net.OpError
+		return !errors.As(err, &opErr) || opErr.Op != "dial"
+	}
+
+	switch robotErr.Status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rateLimitWait returns how long to wait before retrying a rate-limited request: the interval Robot counts the
+// requests of the endpoint over, after which the limit has been reset for sure. Robot limits last up to an hour,
+// so ok is false if the interval is longer than maxBackoff or than the time left before the context deadline:
+// the request fails right away instead of blocking the operation.
+func rateLimitWait(ctx context.Context, robotErr *Error, maxBackoff time.Duration) (wait time.Duration, ok bool) {
+	wait = time.Duration(robotErr.Interval) * time.Second
+	if wait > maxBackoff {
+		return wait, false
+	}
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < wait {
+		return wait, false
+	}
+	return wait, true
+}
+
+// backoff returns the wait before the next attempt: exponential growth capped by maxBackoff, with jitter
+// so that parallel Terraform operations do not retry in lockstep.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.maxBackoff
+	if attempt < 32 && minBackoff<<attempt < c.maxBackoff {
+		wait = minBackoff << attempt
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
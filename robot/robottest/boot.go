@@ -0,0 +1,412 @@
+package robottest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// bootParam is a parameter of a boot profile. Inactive profiles list the options of parameters that have any,
+// active ones report the configured value.
+type bootParam struct {
+	name     string
+	options  []string
+	required bool
+	number   bool
+}
+
+var bootParams = map[string][]bootParam{
+	robot.BootProfileRescue: {
+		{name: "os", options: []string{"linux", "vkvm"}, required: true},
+		{name: "arch", options: []string{"64", "32"}, number: true},
+		{name: "keyboard", options: []string{"us", "de", "fr", "ch", "uk"}},
+	},
+	robot.BootProfileLinux: {
+		{name: "dist", options: []string{"Debian 12 base", "Ubuntu 22.04 LTS base", "Rocky Linux 9 base"}, required: true},
+		{name: "arch", options: []string{"64"}, number: true},
+		{name: "lang", options: []string{"en", "de"}, required: true},
+	},
+	robot.BootProfileVNC: {
+		{name: "dist", options: []string{"Debian-12", "Ubuntu-22.04", "CentOS Stream 9"}, required: true},
+		{name: "arch", options: []string{"64", "32"}, required: true, number: true},
+		{name: "lang", options: []string{"en_US", "de_DE"}, required: true},
+	},
+	robot.BootProfileWindows: {
+		{name: "dist", options: []string{"standard", "datacenter"}},
+		{name: "lang", options: []string{"en", "de"}, required: true},
+	},
+	robot.BootProfilePlesk: {
+		{name: "dist", options: []string{"Debian 12 base", "Ubuntu 22.04 LTS base"}, required: true},
+		{name: "arch", options: []string{"64"}, required: true, number: true},
+		{name: "lang", options: []string{"en", "de"}, required: true},
+		{name: "hostname", required: true},
+	},
+	robot.BootProfileCPanel: {
+		{name: "dist", options: []string{"AlmaLinux 8 + cPanel"}, required: true},
+		{name: "arch", options: []string{"64"}, required: true, number: true},
+		{name: "lang", options: []string{"en"}, required: true},
+		{name: "hostname", required: true},
+	},
+}
+
+var bootProfiles = []string{
+	robot.BootProfileRescue,
+	robot.BootProfileLinux,
+	robot.BootProfileVNC,
+	robot.BootProfileWindows,
+	robot.BootProfilePlesk,
+	robot.BootProfileCPanel,
+}
+
+// keyProfiles are the profiles configured with authorized keys and reporting host keys.
+var keyProfiles = map[string]bool{
+	robot.BootProfileRescue: true,
+	robot.BootProfileLinux:  true,
+}
+
+type bootProfileState struct {
+	active         bool
+	values         map[string]string
+	password       string
+	authorizedKeys []robot.Key
+	hostKeys       []robot.Key
+	// hostSigners holds the private keys of hostKeys, for tests acting as the booted system.
+	hostSigners []ssh.Signer
+
+	// booting is set by a reset; the profile is consumed once pendingReads reads of it are done.
+	booting      bool
+	pendingReads int
+}
+
+type bootState struct {
+	server   robot.Server
+	profiles map[string]*bootProfileState
+	// last holds the configuration each profile with keys was last activated with.
+	last map[string]*bootProfileState
+}
+
+func newBootState(server robot.Server) *bootState {
+	available := map[string]bool{
+		robot.BootProfileRescue:  server.Rescue,
+		robot.BootProfileLinux:   true,
+		robot.BootProfileVNC:     server.VNC,
+		robot.BootProfileWindows: server.Windows,
+		robot.BootProfilePlesk:   server.Plesk,
+		robot.BootProfileCPanel:  server.CPanel,
+	}
+
+	boot := &bootState{server: server, profiles: make(map[string]*bootProfileState), last: make(map[string]*bootProfileState)}
+	for profile, ok := range available {
+		if ok {
+			boot.profiles[profile] = &bootProfileState{}
+		}
+	}
+	return boot
+}
+
+// read advances the boot after a reset and returns the profile document.
+func (b *bootState) read(profile string) map[string]interface{} {
+	state := b.profiles[profile]
+	if state.booting && consumeRead(&state.pendingReads) {
+		state.active = false
+		state.booting = false
+		state.password = ""
+	}
+	return b.document(profile)
+}
+
+func (b *bootState) document(profile string) map[string]interface{} {
+	return b.profileDocument(profile, b.profiles[profile])
+}
+
+func (b *bootState) profileDocument(profile string, state *bootProfileState) map[string]interface{} {
+	doc := map[string]interface{}{
+		"server_ip":       b.server.ServerIP,
+		"server_ipv6_net": b.server.ServerIPv6,
+		"server_number":   b.server.ServerNumber,
+		"active":          state.active,
+		"password":        nil,
+	}
+
+	for _, param := range bootParams[profile] {
+		switch {
+		case state.active && state.values[param.name] == "":
+			doc[param.name] = nil
+		case state.active:
+			doc[param.name] = paramValue(param, state.values[param.name])
+		case param.options == nil:
+			doc[param.name] = nil
+		default:
+			options := make([]interface{}, 0, len(param.options))
+			for _, option := range param.options {
+				options = append(options, paramValue(param, option))
+			}
+			doc[param.name] = options
+		}
+	}
+
+	if state.active {
+		doc["password"] = state.password
+	}
+	if keyProfiles[profile] {
+		doc["authorized_key"] = keyDocuments(state.authorizedKeys)
+		doc["host_key"] = keyDocuments(state.hostKeys)
+	}
+	if profile == robot.BootProfileRescue {
+		doc["boot_time"] = nil
+	}
+	return doc
+}
+
+func paramValue(param bootParam, value string) interface{} {
+	if param.number {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+func keyDocuments(keys []robot.Key) []map[string]interface{} {
+	docs := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		docs = append(docs, map[string]interface{}{"key": map[string]interface{}{
+			"name":        key.Name,
+			"fingerprint": key.Fingerprint,
+			"type":        key.Type,
+			"size":        key.Size,
+		}})
+	}
+	return docs
+}
+
+// bootByNumber resolves the {number} path value to the boot state of a server.
+func (s *Server) bootByNumber(w http.ResponseWriter, r *http.Request) (*bootState, bool) {
+	server, ok := s.serverByNumber(w, r)
+	if !ok {
+		return nil, false
+	}
+	return s.boots[server.ServerNumber], true
+}
+
+// bootProfile resolves the {profile} path value, answering NOT_FOUND for unknown profiles and
+// BOOT_NOT_AVAILABLE for profiles the server doesn't offer.
+func bootProfile(w http.ResponseWriter, r *http.Request, boot *bootState) (string, bool) {
+	profile := r.PathValue("profile")
+	if _, ok := bootParams[profile]; !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Not found")
+		return "", false
+	}
+	if _, ok := boot.profiles[profile]; !ok {
+		writeError(w, http.StatusNotFound, "BOOT_NOT_AVAILABLE", "The boot configuration is not available for this server")
+		return "", false
+	}
+	return profile, true
+}
+
+func (s *Server) getBoot(w http.ResponseWriter, r *http.Request) {
+	boot, ok := s.bootByNumber(w, r)
+	if !ok {
+		return
+	}
+
+	doc := make(map[string]interface{}, len(bootProfiles))
+	for _, profile := range bootProfiles {
+		doc[profile] = nil
+		if _, ok := boot.profiles[profile]; ok {
+			doc[profile] = boot.read(profile)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"boot": doc})
+}
+
+func (s *Server) getBootProfile(w http.ResponseWriter, r *http.Request) {
+	boot, ok := s.bootByNumber(w, r)
+	if !ok {
+		return
+	}
+	profile, ok := bootProfile(w, r, boot)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{profile: boot.read(profile)})
+}
+
+// getLastBootProfile answers the last configuration of the rescue and linux profiles, with the active flag of the
+// profile.
+func (s *Server) getLastBootProfile(w http.ResponseWriter, r *http.Request) {
+	boot, ok := s.bootByNumber(w, r)
+	if !ok {
+		return
+	}
+	profile, ok := bootProfile(w, r, boot)
+	if !ok {
+		return
+	}
+	last, ok := boot.last[profile]
+	if !keyProfiles[profile] || !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "No last boot configuration")
+		return
+	}
+
+	doc := boot.profileDocument(profile, last)
+	doc["active"] = boot.profiles[profile].active
+	writeJSON(w, http.StatusOK, map[string]interface{}{profile: doc})
+}
+
+func (s *Server) activateBootProfile(w http.ResponseWriter, r *http.Request) {
+	boot, ok := s.bootByNumber(w, r)
+	if !ok {
+		return
+	}
+	profile, ok := bootProfile(w, r, boot)
+	if !ok {
+		return
+	}
+
+	var input inputErrors
+	values := make(map[string]string)
+	for _, param := range bootParams[profile] {
+		value := r.Form.Get(param.name)
+		if param.required {
+			value = input.require(r, param.name)
+		}
+		if value != "" && param.options != nil {
+			input.check(contains(param.options, value), param.name)
+		}
+		values[param.name] = value
+	}
+
+	var authorizedKeys []robot.Key
+	if keyProfiles[profile] {
+		for _, fingerprint := range formList(r, "authorized_key") {
+			key, ok := s.keys[fingerprint]
+			input.check(ok, "authorized_key")
+			if ok {
+				authorizedKeys = append(authorizedKeys, *key)
+			}
+		}
+		// Robot doesn't keep the order of the request
+		slices.SortFunc(authorizedKeys, func(a, b robot.Key) int {
+			return strings.Compare(a.Fingerprint, b.Fingerprint)
+		})
+	}
+	if input.write(w) {
+		return
+	}
+
+	for _, state := range boot.profiles {
+		if state.active {
+			writeError(w, http.StatusConflict, "BOOT_ALREADY_ENABLED", "A boot configuration is already active")
+			return
+		}
+	}
+
+	state := boot.profiles[profile]
+	state.active = true
+	state.values = values
+	state.password = randomPassword()
+	state.authorizedKeys = authorizedKeys
+	state.hostKeys = nil
+	state.hostSigners = nil
+	if keyProfiles[profile] {
+		hostKey, signer := randomHostKey()
+		state.hostKeys = []robot.Key{hostKey}
+		state.hostSigners = []ssh.Signer{signer}
+	}
+	if keyProfiles[profile] {
+		last := *state
+		boot.last[profile] = &last
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{profile: boot.document(profile)})
+}
+
+func (s *Server) deactivateBootProfile(w http.ResponseWriter, r *http.Request) {
+	boot, ok := s.bootByNumber(w, r)
+	if !ok {
+		return
+	}
+	profile, ok := bootProfile(w, r, boot)
+	if !ok {
+		return
+	}
+
+	boot.profiles[profile] = &bootProfileState{}
+	writeJSON(w, http.StatusOK, map[string]interface{}{profile: boot.document(profile)})
+}
+
+// startBoot lets the server boot into its active profile after a reset.
+func (b *bootState) startBoot(pendingReads int) {
+	for _, state := range b.profiles {
+		if state.active {
+			state.booting = true
+			state.pendingReads = pendingReads
+		}
+	}
+}
+
+func randomPassword() string {
+	const alphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	password := make([]byte, 12)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			panic(err)
+		}
+		password[i] = alphabet[n.Int64()]
+	}
+	return string(password)
+}
+
+func randomHostKey() (robot.Key, ssh.Signer) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		panic(err)
+	}
+	key := signer.PublicKey()
+	return robot.Key{
+		Fingerprint: ssh.FingerprintLegacyMD5(key),
+		Type:        "ED25519",
+		Size:        256,
+		Data:        string(ssh.MarshalAuthorizedKey(key)),
+	}, signer
+}
+
+// LastHostKeys returns the private host keys profile (rescue or linux) of a server was last activated with, e.g.
+// for a test SSH server standing in for the booted system.
+func (s *Server) LastHostKeys(serverNumber int, profile string) []ssh.Signer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	boot, ok := s.boots[serverNumber]
+	if !ok {
+		return nil
+	}
+	last, ok := boot.last[profile]
+	if !ok {
+		return nil
+	}
+	return last.hostSigners
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package robottest
+
+import (
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type failoverDocument struct {
+	Failover robot.Failover `json:"failover"`
+}
+
+// AddFailover adds a failover IP to the account. Its ServerIP must be the main IP of a server added before.
+func (s *Server) AddFailover(failover robot.Failover) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if server, ok := s.serverByID(failover.ServerIP); ok {
+		failover.ServerNumber = server.ServerNumber
+		failover.ServerIPv6Net = server.ServerIPv6
+	}
+	s.failovers[failover.IP] = &failover
+}
+
+// failoverByIP resolves the {ip} path value.
+func (s *Server) failoverByIP(w http.ResponseWriter, r *http.Request) (*robot.Failover, bool) {
+	failover, ok := s.failovers[r.PathValue("ip")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Failover IP not found")
+	}
+	return failover, ok
+}
+
+func (s *Server) listFailovers(w http.ResponseWriter, r *http.Request) {
+	if len(s.failovers) == 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "No failover IPs found")
+		return
+	}
+
+	response := make([]failoverDocument, 0, len(s.failovers))
+	for _, failover := range s.failovers {
+		response = append(response, failoverDocument{Failover: *failover})
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Failover.IP < response[j].Failover.IP
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getFailover(w http.ResponseWriter, r *http.Request) {
+	failover, ok := s.failoverByIP(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, failoverDocument{Failover: *failover})
+}
+
+func (s *Server) routeFailover(w http.ResponseWriter, r *http.Request) {
+	failover, ok := s.failoverByIP(w, r)
+	if !ok {
+		return
+	}
+
+	var input inputErrors
+	activeServerIP := input.require(r, "active_server_ip")
+	if activeServerIP != "" {
+		_, ok := s.serverByID(activeServerIP)
+		input.check(ok && net.ParseIP(activeServerIP) != nil, "active_server_ip")
+	}
+	if input.write(w) {
+		return
+	}
+
+	if failover.ActiveServerIP == activeServerIP {
+		writeError(w, http.StatusConflict, "FAILOVER_ALREADY_ROUTED", "The failover IP is already routed to this server")
+		return
+	}
+	failover.ActiveServerIP = activeServerIP
+	writeJSON(w, http.StatusOK, failoverDocument{Failover: *failover})
+}
+
+func (s *Server) unrouteFailover(w http.ResponseWriter, r *http.Request) {
+	failover, ok := s.failoverByIP(w, r)
+	if !ok {
+		return
+	}
+	failover.ActiveServerIP = ""
+	writeJSON(w, http.StatusOK, failoverDocument{Failover: *failover})
+}
@@ -0,0 +1,142 @@
+package robottest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Fault is an error the fake answers matching requests with instead of handling them.
+type Fault struct {
+	// Method matches the request method, any method if empty.
+	Method string
+	// Path matches requests whose path starts with it, any path if empty.
+	Path string
+
+	Status  int
+	Code    string
+	Message string
+
+	// Interval is the interval in seconds a RATE_LIMIT_EXCEEDED fault reports the limit for, an hour if 0.
+	Interval int
+
+	// Drop makes the fake handle the request and then close the connection without answering, as when a
+	// response is lost. Status, Code and Message are ignored.
+	Drop bool
+
+	// Times is the number of requests failing before the fault is cleared, 0 for all of them.
+	Times int
+
+	hits int
+}
+
+// RateLimit is the fault Robot answers with once the hourly limit of an endpoint is reached.
+func RateLimit(method string, path string, times int) Fault {
+	return Fault{
+		Method:  method,
+		Path:    path,
+		Status:  http.StatusForbidden,
+		Code:    "RATE_LIMIT_EXCEEDED",
+		Message: "Rate limit exceeded",
+		Times:   times,
+	}
+}
+
+// Unavailable is a 503 as sent while Robot is under maintenance.
+func Unavailable(method string, path string, times int) Fault {
+	return Fault{
+		Method:  method,
+		Path:    path,
+		Status:  http.StatusServiceUnavailable,
+		Code:    "SERVICE_UNAVAILABLE",
+		Message: "Service unavailable",
+		Times:   times,
+	}
+}
+
+// InternalError is a 500 with the given code, e.g. BOOT_ACTIVATION_FAILED.
+func InternalError(method string, path string, code string, times int) Fault {
+	return Fault{
+		Method:  method,
+		Path:    path,
+		Status:  http.StatusInternalServerError,
+		Code:    code,
+		Message: "Internal error",
+		Times:   times,
+	}
+}
+
+// InProcess is the conflict Robot answers with while an earlier change is applied, e.g. FIREWALL_IN_PROCESS
+// or VSWITCH_IN_PROCESS.
+func InProcess(method string, path string, code string, times int) Fault {
+	return Fault{
+		Method:  method,
+		Path:    path,
+		Status:  http.StatusConflict,
+		Code:    code,
+		Message: "The object is currently in process",
+		Times:   times,
+	}
+}
+
+// LostResponse is a request carried out by Robot whose response never arrives, e.g. because of a timeout.
+func LostResponse(method string, path string, times int) Fault {
+	return Fault{
+		Method: method,
+		Path:   path,
+		Drop:   true,
+		Times:  times,
+	}
+}
+
+// InjectFault makes the fake answer the requests matching f with its error.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method || !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		f.hits++
+		if f.Times > 0 && f.hits >= f.Times {
+			s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// drop closes the connection of a request without answering it.
+func drop(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic("robottest: the response writer can't drop the connection")
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(err)
+	}
+	conn.Close()
+}
+
+func (f *Fault) write(w http.ResponseWriter) {
+	doc := errorDocument{Status: f.Status, Code: f.Code, Message: f.Message}
+	if f.Code == "RATE_LIMIT_EXCEEDED" {
+		doc.MaxRequest = 200
+		doc.Interval = f.Interval
+		if doc.Interval == 0 {
+			doc.Interval = 3600
+		}
+	}
+	writeErrorDocument(w, doc)
+}
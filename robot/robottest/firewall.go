@@ -0,0 +1,208 @@
+package robottest
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// maxFirewallRules is the number of rules Robot allows per direction.
+const maxFirewallRules = 10
+
+var (
+	firewallPortPattern = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*$`)
+	firewallRulePattern = regexp.MustCompile(`^rules\[(input|output)\]\[(\d+)\]\[(\w+)\]$`)
+	firewallProtocols   = []string{"tcp", "udp", "gre", "icmp", "ipip", "ah", "esp"}
+)
+
+// firewallState is the firewall of a server. Changes are applied asynchronously: until pendingReads reads are
+// done the firewall reports "in process" and refuses further changes.
+type firewallState struct {
+	firewall     robot.Firewall
+	status       string
+	processing   bool
+	pendingReads int
+}
+
+type firewallDocument struct {
+	Firewall robot.Firewall `json:"firewall"`
+}
+
+func (f *firewallState) read() robot.Firewall {
+	if f.processing && consumeRead(&f.pendingReads) {
+		f.processing = false
+		f.firewall.Status = f.status
+	}
+	return f.firewall
+}
+
+func (f *firewallState) apply(status string, whitelistHOS bool, rules robot.FirewallRules, pendingReads int) {
+	f.firewall.Status = "in process"
+	f.firewall.WhitelistHOS = whitelistHOS
+	f.firewall.Rules = rules
+	f.status = status
+	f.processing = true
+	f.pendingReads = pendingReads
+}
+
+// firewallByID resolves the {id} path value, a server number or main IP.
+func (s *Server) firewallByID(w http.ResponseWriter, r *http.Request) (*firewallState, bool) {
+	server, ok := s.serverByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "SERVER_NOT_FOUND", "Server not found")
+		return nil, false
+	}
+	return s.firewalls[server.ServerNumber], true
+}
+
+func (s *Server) getFirewall(w http.ResponseWriter, r *http.Request) {
+	firewall, ok := s.firewallByID(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, firewallDocument{Firewall: firewall.read()})
+}
+
+func (s *Server) updateFirewall(w http.ResponseWriter, r *http.Request) {
+	firewall, ok := s.firewallByID(w, r)
+	if !ok {
+		return
+	}
+	if firewall.processing {
+		writeError(w, http.StatusConflict, "FIREWALL_IN_PROCESS", "The firewall is currently in process")
+		return
+	}
+
+	var input inputErrors
+	status := input.require(r, "status")
+	if status != "" {
+		input.check(status == "active" || status == "disabled", "status")
+	}
+	whitelistHOS := firewall.firewall.WhitelistHOS
+	if value := r.Form.Get("whitelist_hos"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		input.check(err == nil, "whitelist_hos")
+		whitelistHOS = parsed
+	}
+	rules := parseFirewallRules(r, &input)
+	if input.write(w) {
+		return
+	}
+
+	if len(rules.Input) > maxFirewallRules || len(rules.Output) > maxFirewallRules {
+		writeError(w, http.StatusConflict, "FIREWALL_RULE_LIMIT_EXCEEDED", "Maximum number of rules exceeded")
+		return
+	}
+
+	firewall.apply(status, whitelistHOS, rules, s.processingReads)
+	writeJSON(w, http.StatusOK, firewallDocument{Firewall: firewall.firewall})
+}
+
+func (s *Server) deleteFirewall(w http.ResponseWriter, r *http.Request) {
+	firewall, ok := s.firewallByID(w, r)
+	if !ok {
+		return
+	}
+	if firewall.processing {
+		writeError(w, http.StatusConflict, "FIREWALL_IN_PROCESS", "The firewall is currently in process")
+		return
+	}
+
+	firewall.apply("disabled", firewall.firewall.WhitelistHOS, robot.FirewallRules{
+		Input:  []robot.FirewallRule{},
+		Output: []robot.FirewallRule{},
+	}, s.processingReads)
+	writeJSON(w, http.StatusOK, firewallDocument{Firewall: firewall.firewall})
+}
+
+// parseFirewallRules decodes the rules[direction][index][field] parameters, validating them like Robot.
+func parseFirewallRules(r *http.Request, input *inputErrors) robot.FirewallRules {
+	fields := map[string]map[int]map[string]string{"input": {}, "output": {}}
+	for name, values := range r.Form {
+		match := firewallRulePattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[2])
+		if fields[match[1]][index] == nil {
+			fields[match[1]][index] = make(map[string]string)
+		}
+		fields[match[1]][index][match[3]] = values[0]
+	}
+
+	rules := robot.FirewallRules{Input: []robot.FirewallRule{}, Output: []robot.FirewallRule{}}
+	for _, direction := range []string{"input", "output"} {
+		indexes := make([]int, 0, len(fields[direction]))
+		for index := range fields[direction] {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+
+		for _, index := range indexes {
+			rule := parseFirewallRule(fields[direction][index], fmt.Sprintf("rules[%s][%d]", direction, index), input)
+			if direction == "input" {
+				rules.Input = append(rules.Input, rule)
+			} else {
+				rules.Output = append(rules.Output, rule)
+			}
+		}
+	}
+	return rules
+}
+
+func parseFirewallRule(fields map[string]string, prefix string, input *inputErrors) robot.FirewallRule {
+	param := func(field string) string {
+		return fmt.Sprintf("%s[%s]", prefix, field)
+	}
+
+	rule := robot.FirewallRule{
+		IPVersion: fields["ip_version"],
+		Name:      fields["name"],
+		DstIP:     fields["dst_ip"],
+		DstPort:   fields["dst_port"],
+		SrcIP:     fields["src_ip"],
+		SrcPort:   fields["src_port"],
+		Protocol:  fields["protocol"],
+		TCPFlags:  fields["tcp_flags"],
+		Action:    fields["action"],
+	}
+
+	if rule.Action == "" {
+		input.missing = append(input.missing, param("action"))
+	} else {
+		input.check(rule.Action == "accept" || rule.Action == "discard", param("action"))
+	}
+	if rule.IPVersion != "" {
+		input.check(rule.IPVersion == "ipv4" || rule.IPVersion == "ipv6", param("ip_version"))
+	}
+	if rule.Protocol != "" {
+		input.check(contains(firewallProtocols, rule.Protocol), param("protocol"))
+	}
+	if rule.DstPort != "" {
+		input.check(firewallPortPattern.MatchString(rule.DstPort), param("dst_port"))
+	}
+	if rule.SrcPort != "" {
+		input.check(firewallPortPattern.MatchString(rule.SrcPort), param("src_port"))
+	}
+	if rule.DstIP != "" {
+		input.check(validNetwork(rule.DstIP), param("dst_ip"))
+	}
+	if rule.SrcIP != "" {
+		input.check(validNetwork(rule.SrcIP), param("src_ip"))
+	}
+	return rule
+}
+
+func validNetwork(value string) bool {
+	if strings.Contains(value, "/") {
+		_, _, err := net.ParseCIDR(value)
+		return err == nil
+	}
+	return net.ParseIP(value) != nil
+}
@@ -0,0 +1,109 @@
+package robottest
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type ipDocument struct {
+	IP robot.IP `json:"ip"`
+}
+
+// ipByAddress resolves the {ip} path value.
+func (s *Server) ipByAddress(w http.ResponseWriter, r *http.Request) (*robot.IP, bool) {
+	ip, ok := s.ips[r.PathValue("ip")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "IP_NOT_FOUND", "IP not found")
+	}
+	return ip, ok
+}
+
+func (s *Server) listIPs(w http.ResponseWriter, r *http.Request) {
+	if len(s.ips) == 0 {
+		writeError(w, http.StatusNotFound, "IP_NOT_FOUND", "No IPs found")
+		return
+	}
+
+	response := make([]ipDocument, 0, len(s.ips))
+	for _, ip := range s.ips {
+		response = append(response, ipDocument{IP: *ip})
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].IP.IP < response[j].IP.IP
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getIP(w http.ResponseWriter, r *http.Request) {
+	ip, ok := s.ipByAddress(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, ipDocument{IP: *ip})
+}
+
+func (s *Server) updateIP(w http.ResponseWriter, r *http.Request) {
+	ip, ok := s.ipByAddress(w, r)
+	if !ok {
+		return
+	}
+
+	var input inputErrors
+	warnings := ip.TrafficWarnings
+	if value := r.Form.Get("traffic_warnings"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		input.check(err == nil, "traffic_warnings")
+		warnings = parsed
+	}
+	limits := map[string]*int{
+		"traffic_hourly":  &ip.TrafficHourly,
+		"traffic_daily":   &ip.TrafficDaily,
+		"traffic_monthly": &ip.TrafficMonthly,
+	}
+	parsed := make(map[string]int)
+	for name := range limits {
+		if value := r.Form.Get(name); value != "" {
+			limit, err := strconv.Atoi(value)
+			input.check(err == nil && limit > 0, name)
+			parsed[name] = limit
+		}
+	}
+	if input.write(w) {
+		return
+	}
+
+	ip.TrafficWarnings = warnings
+	for name, limit := range parsed {
+		*limits[name] = limit
+	}
+	writeJSON(w, http.StatusOK, ipDocument{IP: *ip})
+}
+
+// gateway returns the first address of the /26 of an IPv4 address, as for Hetzner's main IPs.
+func gateway(ip string) string {
+	network := net.ParseIP(ip).To4()
+	if network == nil {
+		return ""
+	}
+	network = network.Mask(net.CIDRMask(26, 32))
+	network[3]++
+	return network.String()
+}
+
+// broadcast returns the last address of the /26 of an IPv4 address.
+func broadcast(ip string) string {
+	address := net.ParseIP(ip).To4()
+	if address == nil {
+		return ""
+	}
+	mask := net.CIDRMask(26, 32)
+	result := make(net.IP, len(address))
+	for i := range address {
+		result[i] = address[i] | ^mask[i]
+	}
+	return result.String()
+}
@@ -0,0 +1,147 @@
+package robottest
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type keyDocument struct {
+	Key robot.Key `json:"key"`
+}
+
+// AddKey adds a public key in OpenSSH or SSH2 format to the account.
+func (s *Server) AddKey(name string, data string) (robot.Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := newKey(name, data)
+	if err != nil {
+		return robot.Key{}, err
+	}
+	s.keys[key.Fingerprint] = &key
+	return key, nil
+}
+
+func newKey(name string, data string) (robot.Key, error) {
+	publicKey, err := robot.ParsePublicKey(data)
+	if err != nil {
+		return robot.Key{}, err
+	}
+
+	key := robot.Key{
+		Name:        name,
+		Fingerprint: ssh.FingerprintLegacyMD5(publicKey),
+		Data:        strings.TrimSpace(data),
+		CreatedAt:   time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	switch publicKey.Type() {
+	case ssh.KeyAlgoRSA:
+		key.Type = "RSA"
+		if cryptoKey, ok := publicKey.(ssh.CryptoPublicKey); ok {
+			if rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey); ok {
+				key.Size = rsaKey.N.BitLen()
+			}
+		}
+	case ssh.KeyAlgoED25519:
+		key.Type = "ED25519"
+		key.Size = 256
+	case ssh.KeyAlgoECDSA256:
+		key.Type = "ECDSA"
+		key.Size = 256
+	case ssh.KeyAlgoECDSA384:
+		key.Type = "ECDSA"
+		key.Size = 384
+	case ssh.KeyAlgoECDSA521:
+		key.Type = "ECDSA"
+		key.Size = 521
+	default:
+		key.Type = strings.ToUpper(publicKey.Type())
+	}
+	return key, nil
+}
+
+// keyByFingerprint resolves the {fingerprint} path value.
+func (s *Server) keyByFingerprint(w http.ResponseWriter, r *http.Request) (*robot.Key, bool) {
+	key, ok := s.keys[r.PathValue("fingerprint")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Key not found")
+	}
+	return key, ok
+}
+
+func (s *Server) listKeys(w http.ResponseWriter, r *http.Request) {
+	if len(s.keys) == 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "No keys found")
+		return
+	}
+
+	response := make([]keyDocument, 0, len(s.keys))
+	for _, key := range s.keys {
+		response = append(response, keyDocument{Key: *key})
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Key.Name < response[j].Key.Name
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) createKey(w http.ResponseWriter, r *http.Request) {
+	var input inputErrors
+	name := input.require(r, "name")
+	data := input.require(r, "data")
+	key, err := newKey(name, data)
+	if data != "" {
+		input.check(err == nil, "data")
+	}
+	if input.write(w) {
+		return
+	}
+
+	if _, ok := s.keys[key.Fingerprint]; ok {
+		writeError(w, http.StatusConflict, "KEY_ALREADY_EXISTS", "The key already exists")
+		return
+	}
+	s.keys[key.Fingerprint] = &key
+	writeJSON(w, http.StatusCreated, keyDocument{Key: key})
+}
+
+func (s *Server) getKey(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.keyByFingerprint(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, keyDocument{Key: *key})
+}
+
+func (s *Server) renameKey(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.keyByFingerprint(w, r)
+	if !ok {
+		return
+	}
+
+	var input inputErrors
+	name := input.require(r, "name")
+	if input.write(w) {
+		return
+	}
+
+	key.Name = name
+	writeJSON(w, http.StatusOK, keyDocument{Key: *key})
+}
+
+func (s *Server) deleteKey(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.keyByFingerprint(w, r)
+	if !ok {
+		return
+	}
+	delete(s.keys, key.Fingerprint)
+	writeJSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,99 @@
+package robottest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type rdnsDocument struct {
+	RDNS robot.RDNS `json:"rdns"`
+}
+
+// SetRDNS sets the PTR record of an IP of the account.
+func (s *Server) SetRDNS(ip string, ptr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rdns[ip] = ptr
+}
+
+// ownsIP reports whether ip is one of the IPs of the account's servers.
+func (s *Server) ownsIP(ip string) bool {
+	if _, ok := s.ips[ip]; ok {
+		return true
+	}
+	_, ok := s.failovers[ip]
+	return ok
+}
+
+func (s *Server) listRDNS(w http.ResponseWriter, r *http.Request) {
+	if len(s.rdns) == 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "No reverse DNS entries found")
+		return
+	}
+
+	response := make([]rdnsDocument, 0, len(s.rdns))
+	for ip, ptr := range s.rdns {
+		response = append(response, rdnsDocument{RDNS: robot.RDNS{IP: ip, PTR: ptr}})
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].RDNS.IP < response[j].RDNS.IP
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getRDNS(w http.ResponseWriter, r *http.Request) {
+	ip := r.PathValue("ip")
+	ptr, ok := s.rdns[ip]
+	if !ok {
+		writeError(w, http.StatusNotFound, "RDNS_NOT_FOUND", "Reverse DNS entry not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, rdnsDocument{RDNS: robot.RDNS{IP: ip, PTR: ptr}})
+}
+
+// createRDNS creates a PTR record, refusing to replace an existing one.
+func (s *Server) createRDNS(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.rdns[r.PathValue("ip")]; ok {
+		writeError(w, http.StatusConflict, "RDNS_ALREADY_EXISTS", "The reverse DNS entry already exists")
+		return
+	}
+	s.writeRDNS(w, r, http.StatusCreated)
+}
+
+// setRDNS creates or replaces a PTR record.
+func (s *Server) setRDNS(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	if _, ok := s.rdns[r.PathValue("ip")]; !ok {
+		status = http.StatusCreated
+	}
+	s.writeRDNS(w, r, status)
+}
+
+func (s *Server) writeRDNS(w http.ResponseWriter, r *http.Request, status int) {
+	ip := r.PathValue("ip")
+	if !s.ownsIP(ip) {
+		writeError(w, http.StatusNotFound, "IP_NOT_FOUND", "IP not found")
+		return
+	}
+
+	var input inputErrors
+	ptr := input.require(r, "ptr")
+	if input.write(w) {
+		return
+	}
+
+	s.rdns[ip] = ptr
+	writeJSON(w, status, rdnsDocument{RDNS: robot.RDNS{IP: ip, PTR: ptr}})
+}
+
+func (s *Server) deleteRDNS(w http.ResponseWriter, r *http.Request) {
+	ip := r.PathValue("ip")
+	if _, ok := s.rdns[ip]; !ok {
+		writeError(w, http.StatusNotFound, "RDNS_NOT_FOUND", "Reverse DNS entry not found")
+		return
+	}
+	delete(s.rdns, ip)
+	writeJSON(w, http.StatusOK, nil)
+}
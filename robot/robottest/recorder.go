@@ -0,0 +1,424 @@
+package robottest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// RecorderMode selects whether a Recorder talks to the webservice or answers from its cassette.
+type RecorderMode int
+
+const (
+	// Replay answers every request with the next interaction of the cassette.
+	Replay RecorderMode = iota
+	// Record sends the requests to the webservice and writes the interactions to the cassette on Close.
+	Record
+)
+
+// Cassette is the fixture file of a Recorder: the interactions with the webservice in the order they happened.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response the webservice gave.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request. The credentials are not recorded, the values of sensitive
+// parameters are replaced as in the audit journal (see robot.RedactParams), and the identifiers of the
+// account as in the responses.
+type CassetteRequest struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Form   map[string][]string `json:"form,omitempty"`
+}
+
+// CassetteResponse is a recorded response. Passwords in the body are replaced by "***", as are the
+// credentials of the recording client wherever they show up and the data of SSH keys the client didn't
+// send itself. Server numbers and IP addresses are replaced by stand-ins, see identifiers.
+type CassetteResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// Text holds bodies that aren't JSON.
+	Text string `json:"text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording the interactions of a client with the Robot webservice into a
+// cassette, or replaying them from it. It plugs into a client with robot.WithHTTPClient:
+//
+//	recorder, err := robottest.NewRecorder("testdata/recorded/boot.json", robottest.Replay)
+//	client := robot.NewClient(username, password, robot.WithHTTPClient(&http.Client{Transport: recorder}))
+//	...
+//	err = recorder.Close()
+//
+// Replay expects the requests in the recorded order, with the same method, path and form.
+type Recorder struct {
+	// Transport sends the requests in Record mode, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+
+	path string
+	mode RecorderMode
+
+	mu       sync.Mutex
+	cassette Cassette
+	next     int
+	ids      identifiers
+}
+
+// NewRecorder returns a recorder for the cassette at path. In Replay mode the cassette must exist.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == Record {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("unable to decode cassette %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	form, err := requestForm(req)
+	if err != nil {
+		return nil, err
+	}
+	request := CassetteRequest{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Form:   normalizeForm(robot.RedactParams(form)),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Record {
+		return r.record(req, form, request)
+	}
+	return r.replay(req, request)
+}
+
+func (r *Recorder) record(req *http.Request, form url.Values, request CassetteRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	for _, values := range form {
+		for _, value := range values {
+			r.ids.sent(value)
+		}
+	}
+	request.Path = r.ids.scrubPath(request.Path)
+	request.Form = r.ids.scrubForm(request.Form)
+
+	response := CassetteResponse{Status: resp.StatusCode}
+	scrubbed := scrubCredentials(req, body)
+	if json.Valid(scrubbed) {
+		response.Body, err = scrubBody(scrubbed, &r.ids)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		response.Text = r.ids.scrubText(string(scrubbed))
+	}
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: request, Response: response})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, request CassetteRequest) (*http.Response, error) {
+	if r.next >= len(r.cassette.Interactions) {
+		return nil, fmt.Errorf("cassette %s: unexpected request %s %s after the last interaction", r.path, request.Method, request.Path)
+	}
+	interaction := r.cassette.Interactions[r.next]
+	if interaction.Request.Method != request.Method || interaction.Request.Path != request.Path ||
+		!reflect.DeepEqual(normalizeForm(interaction.Request.Form), request.Form) {
+		return nil, fmt.Errorf("cassette %s: interaction %d is %s %s %v, got %s %s %v", r.path, r.next,
+			interaction.Request.Method, interaction.Request.Path, interaction.Request.Form,
+			request.Method, request.Path, request.Form)
+	}
+	r.next++
+
+	body := []byte(interaction.Response.Text)
+	if interaction.Response.Body != nil {
+		body = interaction.Response.Body
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Close writes the cassette in Record mode. In Replay mode it fails if interactions were left unused.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Replay {
+		if r.next < len(r.cassette.Interactions) {
+			return fmt.Errorf("cassette %s: %d of %d interactions were not replayed", r.path,
+				len(r.cassette.Interactions)-r.next, len(r.cassette.Interactions))
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// requestForm reads the form of a request without consuming its body.
+func requestForm(req *http.Request) (url.Values, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return url.ParseQuery(string(body))
+}
+
+// normalizeForm maps empty forms to nil, so that forms read back from a cassette compare equal.
+func normalizeForm(form map[string][]string) map[string][]string {
+	if len(form) == 0 {
+		return nil
+	}
+	return form
+}
+
+// scrubCredentials replaces the Basic auth credentials of a request wherever they show up in the response body.
+func scrubCredentials(req *http.Request, body []byte) []byte {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return body
+	}
+	text := string(body)
+	for _, secret := range []string{password, username} {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, "***")
+		}
+	}
+	return []byte(text)
+}
+
+// scrubBody replaces the string values of sensitive attributes, e.g. rescue passwords, and the identifiers of
+// the account. The body is indented with the cassette, so that changes of the webservice show up as readable
+// fixture diffs.
+func scrubBody(body []byte, ids *identifiers) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(scrubValue(value, ids))
+}
+
+func scrubValue(value interface{}, ids *identifiers) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			switch item := item.(type) {
+			case string:
+				switch {
+				case robot.IsSensitiveField(key):
+					value[key] = "***"
+				case key == "data" && !ids.wasSent(item):
+					value[key] = "***"
+				default:
+					value[key] = ids.scrubText(item)
+				}
+				continue
+			case json.Number:
+				if key == "server_number" {
+					value[key] = json.Number(ids.server(item.String()))
+				}
+				continue
+			}
+			value[key] = scrubValue(item, ids)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = scrubValue(item, ids)
+		}
+	case string:
+		return ids.scrubText(value)
+	}
+	return value
+}
+
+// serverFamilies are the endpoint families whose paths start with a server number or IP, e.g. /boot/321/rescue.
+var serverFamilies = map[string]bool{"server": true, "boot": true, "reset": true, "firewall": true, "wol": true}
+
+// ipPattern matches the IPv4 and IPv6 addresses embedded in a string, e.g. in a reverse DNS name.
+var ipPattern = regexp.MustCompile(`[0-9]+(\.[0-9]+){3}|[0-9a-fA-F]*:[0-9a-fA-F:]*:[0-9a-fA-F]*`)
+
+// testNets are the IPv4 networks reserved for documentation (RFC 5737).
+var testNets = []string{"192.0.2", "198.51.100", "203.0.113"}
+
+// identifiers replaces the identifiers of the recording account with stand-ins: server numbers with numbers
+// counting up from 321, IPv4 addresses with addresses of the documentation networks and IPv6 addresses with
+// the same interface ID in a /64 of the documentation prefix 2001:db8::/32. The same identifier always gets
+// the same stand-in within a cassette, so that requests built from recorded responses, e.g. /boot/321 from the
+// server list, match on replay.
+type identifiers struct {
+	servers  map[string]string
+	ipv4     map[string]string
+	ipv6Nets map[[8]byte][8]byte
+	// sentValues are the request parameters of the client, e.g. the data of its test keys, which are not
+	// secrets of the account.
+	sentValues map[string]bool
+}
+
+func (ids *identifiers) sent(value string) {
+	if ids.sentValues == nil {
+		ids.sentValues = make(map[string]bool)
+	}
+	ids.sentValues[value] = true
+}
+
+func (ids *identifiers) wasSent(value string) bool {
+	return ids.sentValues[value]
+}
+
+func (ids *identifiers) server(number string) string {
+	if ids.servers == nil {
+		ids.servers = make(map[string]string)
+	}
+	standIn, ok := ids.servers[number]
+	if !ok {
+		standIn = strconv.Itoa(321 + len(ids.servers))
+		ids.servers[number] = standIn
+	}
+	return standIn
+}
+
+// ip returns the stand-in of an IP address, or ok false if s isn't one.
+func (ids *identifiers) ip(s string) (standIn string, ok bool) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || addr.Zone() != "" {
+		return "", false
+	}
+
+	if addr.Is4() {
+		if ids.ipv4 == nil {
+			ids.ipv4 = make(map[string]string)
+		}
+		standIn, ok := ids.ipv4[s]
+		if !ok {
+			n := len(ids.ipv4)
+			if n >= len(testNets)*254 {
+				return "***", true
+			}
+			standIn = fmt.Sprintf("%s.%d", testNets[n/254], n%254+1)
+			ids.ipv4[s] = standIn
+		}
+		return standIn, true
+	}
+
+	if ids.ipv6Nets == nil {
+		ids.ipv6Nets = make(map[[8]byte][8]byte)
+	}
+	bytes := addr.As16()
+	var network [8]byte
+	copy(network[:], bytes[:8])
+	standInNet, ok := ids.ipv6Nets[network]
+	if !ok {
+		n := len(ids.ipv6Nets) + 1
+		standInNet = [8]byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, byte(n >> 8), byte(n)}
+		ids.ipv6Nets[network] = standInNet
+	}
+	copy(bytes[:8], standInNet[:])
+	return netip.AddrFrom16(bytes).String(), true
+}
+
+// scrubText replaces the IP addresses in s, either s as a whole or embedded in it.
+func (ids *identifiers) scrubText(s string) string {
+	if standIn, ok := ids.ip(s); ok {
+		return standIn
+	}
+	return ipPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if standIn, ok := ids.ip(match); ok {
+			return standIn
+		}
+		return match
+	})
+}
+
+// scrubPath replaces the server number or IP address a path starts with, and any other IP address in it.
+func (ids *identifiers) scrubPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if i == 2 && serverFamilies[segments[1]] && segment != "" && strings.Trim(segment, "0123456789") == "" {
+			segments[i] = ids.server(segment)
+			continue
+		}
+		segments[i] = ids.scrubText(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// scrubForm replaces the server numbers and IP addresses of a form, e.g. the servers added to a vSwitch.
+func (ids *identifiers) scrubForm(form map[string][]string) map[string][]string {
+	for name, values := range form {
+		for i, value := range values {
+			if strings.HasPrefix(name, "server") && value != "" && strings.Trim(value, "0123456789") == "" {
+				values[i] = ids.server(value)
+				continue
+			}
+			values[i] = ids.scrubText(value)
+		}
+		form[name] = values
+	}
+	return form
+}
@@ -0,0 +1,64 @@
+package robottest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type resetDocument struct {
+	Reset robot.Reset `json:"reset"`
+}
+
+func (s *Server) listResets(w http.ResponseWriter, r *http.Request) {
+	numbers := make([]int, 0, len(s.resets))
+	for number := range s.resets {
+		numbers = append(numbers, number)
+	}
+	if len(numbers) == 0 {
+		writeError(w, http.StatusNotFound, "SERVER_NOT_FOUND", "No servers found")
+		return
+	}
+	sort.Ints(numbers)
+
+	response := make([]resetDocument, 0, len(numbers))
+	for _, number := range numbers {
+		response = append(response, resetDocument{Reset: *s.resets[number]})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getReset(w http.ResponseWriter, r *http.Request) {
+	server, ok := s.serverByNumber(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, resetDocument{Reset: *s.resets[server.ServerNumber]})
+}
+
+// executeReset resets a server. Unless it is a manual reset, the server then boots into its active boot profile.
+func (s *Server) executeReset(w http.ResponseWriter, r *http.Request) {
+	server, ok := s.serverByNumber(w, r)
+	if !ok {
+		return
+	}
+	reset := s.resets[server.ServerNumber]
+
+	var input inputErrors
+	resetType := input.require(r, "type")
+	if resetType != "" {
+		input.check(reset.Type.Contains(resetType), "type")
+	}
+	if input.write(w) {
+		return
+	}
+
+	if resetType != robot.ResetManual {
+		s.boots[server.ServerNumber].startBoot(s.processingReads)
+	}
+
+	executed := *reset
+	executed.Type = robot.StringList{resetType}
+	writeJSON(w, http.StatusOK, resetDocument{Reset: executed})
+}
@@ -0,0 +1,330 @@
+// Package robottest provides an in-memory fake of the Robot webservice for tests.
+//
+// The fake keeps the state of servers, boot profiles, resets, firewalls, vSwitches, SSH keys, IPs,
+// reverse DNS entries and failover IPs, checks Basic auth and answers with Robot's JSON documents
+// and error codes:
+//
+//	fake := robottest.NewServer()
+//	defer fake.Close()
+//	fake.AddServer(robot.Server{ServerNumber: 321, ServerIP: "123.123.123.123", Rescue: true})
+//
+//	client := fake.Client()
+//	rescue, err := client.Boot.ActivateRescue(ctx, 321, robot.RescueRequest{OS: "linux"})
+//
+// Faults such as rate limits, 5xx responses, lost responses or objects being "in process" can be injected with
+// InjectFault, and SetProcessingReads delays the asynchronous changes Robot makes.
+package robottest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// Credentials accepted by a Server unless changed.
+const (
+	DefaultUsername = "robot-test"
+	DefaultPassword = "robot-test-password"
+)
+
+// Request is a request received by the fake, with its form parameters.
+type Request struct {
+	Method string
+	Path   string
+	Form   url.Values
+}
+
+// Server is a fake Robot webservice listening on a local httptest server.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password are the credentials the fake accepts.
+	Username string
+	Password string
+
+	mu sync.Mutex
+
+	// processingReads is the number of reads during which firewall changes, vSwitch server assignments
+	// and boots after a reset are still in process.
+	processingReads int
+
+	servers   map[int]*robot.Server
+	boots     map[int]*bootState
+	resets    map[int]*robot.Reset
+	firewalls map[int]*firewallState
+	vSwitches map[int]*vSwitchState
+	keys      map[string]*robot.Key
+	ips       map[string]*robot.IP
+	rdns      map[string]string
+	failovers map[string]*robot.Failover
+
+	nextVSwitchID int
+	faults        []*Fault
+	requests      []Request
+}
+
+// NewServer starts a fake webservice without any servers. It must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		Username:      DefaultUsername,
+		Password:      DefaultPassword,
+		servers:       make(map[int]*robot.Server),
+		boots:         make(map[int]*bootState),
+		resets:        make(map[int]*robot.Reset),
+		firewalls:     make(map[int]*firewallState),
+		vSwitches:     make(map[int]*vSwitchState),
+		keys:          make(map[string]*robot.Key),
+		ips:           make(map[string]*robot.IP),
+		rdns:          make(map[string]string),
+		failovers:     make(map[string]*robot.Failover),
+		nextVSwitchID: 10000,
+	}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// Client returns a client for the fake. It retries without noticeable backoff and has no request budgets,
+// so that tests don't wait.
+func (s *Server) Client(opts ...robot.Option) *robot.Client {
+	budgets := make(map[string]int)
+	for _, family := range []string{"boot", "failover", "firewall", "ip", "key", "rdns", "reset", "server", "vswitch"} {
+		budgets[family] = 0
+	}
+
+	opts = append([]robot.Option{
+		robot.WithBaseURL(s.URL),
+		robot.WithRetries(robot.DefaultMaxRetries, time.Millisecond),
+		robot.WithScheduler(robot.DefaultMaxConcurrentRequests, budgets),
+	}, opts...)
+	return robot.NewClient(s.Username, s.Password, opts...)
+}
+
+// SetProcessingReads sets for how many reads firewall changes, vSwitch server assignments and boots after a
+// reset stay in process. With the default of 0 they are done by the next read.
+func (s *Server) SetProcessingReads(reads int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processingReads = reads
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /server", s.listServers)
+	mux.HandleFunc("GET /server/{number}", s.getServer)
+	mux.HandleFunc("POST /server/{number}", s.renameServer)
+
+	mux.HandleFunc("GET /boot/{number}", s.getBoot)
+	mux.HandleFunc("GET /boot/{number}/{profile}", s.getBootProfile)
+	mux.HandleFunc("GET /boot/{number}/{profile}/last", s.getLastBootProfile)
+	mux.HandleFunc("POST /boot/{number}/{profile}", s.activateBootProfile)
+	mux.HandleFunc("DELETE /boot/{number}/{profile}", s.deactivateBootProfile)
+
+	mux.HandleFunc("GET /reset", s.listResets)
+	mux.HandleFunc("GET /reset/{number}", s.getReset)
+	mux.HandleFunc("POST /reset/{number}", s.executeReset)
+
+	mux.HandleFunc("GET /firewall/{id}", s.getFirewall)
+	mux.HandleFunc("POST /firewall/{id}", s.updateFirewall)
+	mux.HandleFunc("DELETE /firewall/{id}", s.deleteFirewall)
+
+	mux.HandleFunc("GET /vswitch", s.listVSwitches)
+	mux.HandleFunc("POST /vswitch", s.createVSwitch)
+	mux.HandleFunc("GET /vswitch/{id}", s.getVSwitch)
+	mux.HandleFunc("POST /vswitch/{id}", s.updateVSwitch)
+	mux.HandleFunc("DELETE /vswitch/{id}", s.cancelVSwitch)
+	mux.HandleFunc("POST /vswitch/{id}/server", s.addVSwitchServers)
+	mux.HandleFunc("DELETE /vswitch/{id}/server", s.removeVSwitchServers)
+
+	mux.HandleFunc("GET /key", s.listKeys)
+	mux.HandleFunc("POST /key", s.createKey)
+	mux.HandleFunc("GET /key/{fingerprint}", s.getKey)
+	mux.HandleFunc("PUT /key/{fingerprint}", s.renameKey)
+	mux.HandleFunc("DELETE /key/{fingerprint}", s.deleteKey)
+
+	mux.HandleFunc("GET /ip", s.listIPs)
+	mux.HandleFunc("GET /ip/{ip}", s.getIP)
+	mux.HandleFunc("POST /ip/{ip}", s.updateIP)
+
+	mux.HandleFunc("GET /rdns", s.listRDNS)
+	mux.HandleFunc("GET /rdns/{ip}", s.getRDNS)
+	mux.HandleFunc("PUT /rdns/{ip}", s.createRDNS)
+	mux.HandleFunc("POST /rdns/{ip}", s.setRDNS)
+	mux.HandleFunc("DELETE /rdns/{ip}", s.deleteRDNS)
+
+	mux.HandleFunc("GET /failover", s.listFailovers)
+	mux.HandleFunc("GET /failover/{ip}", s.getFailover)
+	mux.HandleFunc("POST /failover/{ip}", s.routeFailover)
+	mux.HandleFunc("DELETE /failover/{ip}", s.unrouteFailover)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Not found")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := parseForm(r); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "Invalid input parameters")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Form: r.Form})
+
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.Username || password != s.Password {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized")
+			return
+		}
+
+		if fault := s.fault(r); fault != nil {
+			if fault.Drop {
+				mux.ServeHTTP(httptest.NewRecorder(), r)
+				drop(w)
+				return
+			}
+			fault.write(w)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// parseForm parses the form of every request; unlike http.Request.ParseForm this includes DELETE bodies,
+// which Robot reads e.g. for vSwitch cancellations.
+func parseForm(r *http.Request) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range r.URL.Query() {
+		form[name] = append(form[name], values...)
+	}
+	r.Form = form
+	r.PostForm = form
+	return nil
+}
+
+// formList returns the values of a list parameter, sent either as name or as name[].
+func formList(r *http.Request, name string) []string {
+	return append(append([]string(nil), r.Form[name]...), r.Form[name+"[]"]...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+type errorDocument struct {
+	Status     int      `json:"status"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+	Missing    []string `json:"missing,omitempty"`
+	Invalid    []string `json:"invalid,omitempty"`
+	MaxRequest int      `json:"max_request,omitempty"`
+	Interval   int      `json:"interval,omitempty"`
+}
+
+func writeErrorDocument(w http.ResponseWriter, doc errorDocument) {
+	writeJSON(w, doc.Status, map[string]interface{}{"error": doc})
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeErrorDocument(w, errorDocument{Status: status, Code: code, Message: message})
+}
+
+// inputErrors collects the missing and invalid parameters of a request.
+type inputErrors struct {
+	missing []string
+	invalid []string
+}
+
+func (e *inputErrors) require(r *http.Request, name string) string {
+	value := r.Form.Get(name)
+	if value == "" {
+		e.missing = append(e.missing, name)
+	}
+	return value
+}
+
+func (e *inputErrors) check(valid bool, name string) {
+	if !valid {
+		e.invalid = append(e.invalid, name)
+	}
+}
+
+// write answers with INVALID_INPUT and returns true if there are errors.
+func (e *inputErrors) write(w http.ResponseWriter) bool {
+	if len(e.missing) == 0 && len(e.invalid) == 0 {
+		return false
+	}
+	writeErrorDocument(w, errorDocument{
+		Status:  http.StatusBadRequest,
+		Code:    "INVALID_INPUT",
+		Message: "Invalid input parameters",
+		Missing: e.missing,
+		Invalid: e.invalid,
+	})
+	return true
+}
+
+// serverByNumber resolves the {number} path value, answering SERVER_NOT_FOUND if there is no such server.
+func (s *Server) serverByNumber(w http.ResponseWriter, r *http.Request) (*robot.Server, bool) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err == nil {
+		if server, ok := s.servers[number]; ok {
+			return server, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "SERVER_NOT_FOUND", "Server not found")
+	return nil, false
+}
+
+// serverByID finds a server by number or main IP, as the /firewall and /vswitch endpoints accept both.
+func (s *Server) serverByID(id string) (*robot.Server, bool) {
+	if number, err := strconv.Atoi(id); err == nil {
+		server, ok := s.servers[number]
+		return server, ok
+	}
+	for _, server := range s.servers {
+		if server.ServerIP == id {
+			return server, true
+		}
+	}
+	return nil, false
+}
+
+// consumeRead counts down the reads an in-process change has left and reports whether it is done.
+func consumeRead(pending *int) bool {
+	if *pending > 0 {
+		*pending--
+		return false
+	}
+	return true
+}
@@ -0,0 +1,394 @@
+package robottest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+const testKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHZ4mA0FFDiw6HTBz9ah1qYmyuyRlYB4FeIZeaCZZ1g3 test@example"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	fake := NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddServer(robot.Server{
+		ServerNumber: 321,
+		ServerIP:     "123.123.123.123",
+		ServerIPv6:   "2a01:4f8:111:4221::",
+		Rescue:       true,
+	})
+	return fake
+}
+
+func TestUnauthorized(t *testing.T) {
+	fake := newTestServer(t)
+
+	client := robot.NewClient("robot-test", "wrong", robot.WithBaseURL(fake.URL))
+	_, err := client.Servers.Get(context.Background(), 321)
+	if !robot.HasErrorCode(err, "UNAUTHORIZED") {
+		t.Fatalf("expected UNAUTHORIZED, got %v", err)
+	}
+}
+
+func TestBootRescue(t *testing.T) {
+	fake := newTestServer(t)
+	client := fake.Client()
+	ctx := context.Background()
+
+	key, err := fake.AddKey("test", testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rescue, err := client.Boot.ActivateRescue(ctx, 321, robot.RescueRequest{
+		OS:             "linux",
+		AuthorizedKeys: []string{key.Fingerprint},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rescue.Active || rescue.Password == "" || rescue.OS.First() != "linux" {
+		t.Fatalf("unexpected rescue configuration: %+v", rescue)
+	}
+	if fingerprints := rescue.AuthorizedKeys.Fingerprints(); len(fingerprints) != 1 || fingerprints[0] != key.Fingerprint {
+		t.Fatalf("unexpected authorized keys: %v", fingerprints)
+	}
+	if len(rescue.HostKeys) != 1 {
+		t.Fatalf("expected a host key, got %v", rescue.HostKeys)
+	}
+	if signers := fake.LastHostKeys(321, robot.BootProfileRescue); len(signers) != 1 || ssh.FingerprintLegacyMD5(signers[0].PublicKey()) != rescue.HostKeys[0].Fingerprint {
+		t.Fatalf("expected the private key of the host key, got %v", signers)
+	}
+
+	_, err = client.Boot.ActivateLinux(ctx, 321, robot.LinuxRequest{Dist: "Debian 12 base", Lang: "en"})
+	if !robot.HasErrorCode(err, "BOOT_ALREADY_ENABLED") {
+		t.Fatalf("expected BOOT_ALREADY_ENABLED, got %v", err)
+	}
+
+	if _, err := client.Reset.Execute(ctx, 321, robot.ResetHardware); err != nil {
+		t.Fatal(err)
+	}
+	rescue, err = client.Boot.GetRescue(ctx, 321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rescue.Active || !rescue.OS.Contains("vkvm") {
+		t.Fatalf("expected the rescue system to be consumed by the reset: %+v", rescue)
+	}
+
+	last, err := client.Boot.GetLastRescue(ctx, 321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Active || last.OS.First() != "linux" || len(last.OS) != 1 || last.AuthorizedKeys.Fingerprints()[0] != key.Fingerprint {
+		t.Fatalf("unexpected last rescue configuration: %+v", last)
+	}
+	if _, err := client.Boot.GetLastLinux(ctx, 321); !robot.IsNotFound(err) {
+		t.Fatalf("expected no last linux configuration, got %v", err)
+	}
+}
+
+func TestBootInvalidInput(t *testing.T) {
+	fake := newTestServer(t)
+
+	_, err := fake.Client().Boot.ActivateLinux(context.Background(), 321, robot.LinuxRequest{Dist: "Windows 95"})
+	robotErr, ok := err.(*robot.Error)
+	if !ok || robotErr.Code != "INVALID_INPUT" {
+		t.Fatalf("expected INVALID_INPUT, got %v", err)
+	}
+	if len(robotErr.Invalid) != 1 || robotErr.Invalid[0] != "dist" || len(robotErr.Missing) != 1 || robotErr.Missing[0] != "lang" {
+		t.Fatalf("unexpected invalid/missing parameters: %v / %v", robotErr.Invalid, robotErr.Missing)
+	}
+}
+
+func TestFirewallInProcess(t *testing.T) {
+	fake := newTestServer(t)
+	fake.SetProcessingReads(1)
+	client := fake.Client()
+	ctx := context.Background()
+
+	request := robot.FirewallRequest{
+		Status: "active",
+		Rules: robot.FirewallRules{Input: []robot.FirewallRule{
+			{IPVersion: "ipv4", Name: "ssh", DstPort: "22", Protocol: "tcp", Action: "accept"},
+		}},
+	}
+	firewall, err := client.Firewall.Update(ctx, "123.123.123.123", request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firewall.Status != "in process" {
+		t.Fatalf("expected the firewall to be in process, got %q", firewall.Status)
+	}
+
+	if _, err := client.Firewall.Update(ctx, "321", request); !robot.HasErrorCode(err, "FIREWALL_IN_PROCESS") {
+		t.Fatalf("expected FIREWALL_IN_PROCESS, got %v", err)
+	}
+
+	for _, status := range []string{"in process", "active"} {
+		firewall, err := client.Firewall.Get(ctx, "321")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if firewall.Status != status {
+			t.Fatalf("expected status %q, got %q", status, firewall.Status)
+		}
+	}
+}
+
+func TestVSwitch(t *testing.T) {
+	fake := newTestServer(t)
+	client := fake.Client()
+	ctx := context.Background()
+
+	vSwitch, err := client.VSwitch.Create(ctx, robot.VSwitchRequest{Name: "private", VLAN: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.VSwitch.Create(ctx, robot.VSwitchRequest{Name: "other", VLAN: 4000}); !robot.IsConflict(err) {
+		t.Fatalf("expected a VLAN conflict, got %v", err)
+	}
+
+	if err := client.VSwitch.AddServers(ctx, vSwitch.ID, []int{321}); err != nil {
+		t.Fatal(err)
+	}
+	vSwitch, err = client.VSwitch.Get(ctx, vSwitch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vSwitch.Servers) != 1 || vSwitch.Servers[0].Status != "ready" {
+		t.Fatalf("unexpected servers: %+v", vSwitch.Servers)
+	}
+
+	if err := client.VSwitch.Cancel(ctx, vSwitch.ID, "now"); err != nil {
+		t.Fatal(err)
+	}
+	vSwitches, err := client.VSwitch.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vSwitches) != 1 || !vSwitches[0].Cancelled {
+		t.Fatalf("expected a cancelled vSwitch, got %+v", vSwitches)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	fake := newTestServer(t)
+	client := fake.Client()
+	ctx := context.Background()
+
+	keys, err := client.Keys.List(ctx)
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v, %v", keys, err)
+	}
+
+	key, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "test", Data: testKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := robot.KeyFingerprint(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Fingerprint != fingerprint || key.Type != "ED25519" || key.Size != 256 {
+		t.Fatalf("unexpected key: %+v", key)
+	}
+
+	if _, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "again", Data: testKey}); !robot.HasErrorCode(err, "KEY_ALREADY_EXISTS") {
+		t.Fatalf("expected KEY_ALREADY_EXISTS, got %v", err)
+	}
+
+	if err := client.Keys.Delete(ctx, fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Keys.Get(ctx, fingerprint); !robot.IsNotFound(err) {
+		t.Fatalf("expected the key to be gone, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	fake := newTestServer(t)
+	client := fake.Client()
+	ctx := context.Background()
+
+	rateLimited := fake.Client(robot.WithRetries(robot.DefaultMaxRetries, robot.DefaultMaxBackoff))
+
+	// an hour is beyond the maximum backoff
+	fake.InjectFault(RateLimit(http.MethodGet, "/server", 1))
+	start := time.Now()
+	if _, err := rateLimited.Servers.Get(ctx, 321); !robot.IsRateLimited(err) || !strings.Contains(err.Error(), "200 requests per 1h0m0s exceeded, retry after ") {
+		t.Fatalf("expected the rate limit to fail right away, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the rate limit to fail right away, took %s", elapsed)
+	}
+
+	// within the maximum backoff, but not the deadline
+	rateLimit := RateLimit(http.MethodGet, "/server", 1)
+	rateLimit.Interval = 20
+	fake.InjectFault(rateLimit)
+	deadlineCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if _, err := rateLimited.Servers.Get(deadlineCtx, 321); !robot.IsRateLimited(err) {
+		t.Fatalf("expected the rate limit to fail right away, got %v", err)
+	}
+
+	rateLimit = RateLimit(http.MethodGet, "/server", 1)
+	rateLimit.Interval = 1
+	fake.InjectFault(rateLimit)
+	if _, err := rateLimited.Servers.Get(deadlineCtx, 321); err != nil {
+		t.Fatalf("expected the rate limit to be retried after its interval, got %v", err)
+	}
+
+	fake.InjectFault(Unavailable(http.MethodPost, "/reset", 0))
+	if _, err := client.Reset.Execute(ctx, 321, robot.ResetSoftware); !robot.HasErrorCode(err, "SERVICE_UNAVAILABLE") {
+		t.Fatalf("expected SERVICE_UNAVAILABLE, got %v", err)
+	}
+
+	fake.ClearFaults()
+	if _, err := client.Reset.Execute(ctx, 321, robot.ResetSoftware); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	fake := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	run := func(mode RecorderMode) string {
+		recorder, err := NewRecorder(path, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := fake.Client(robot.WithHTTPClient(&http.Client{Transport: recorder}))
+
+		rescue, err := client.Boot.ActivateRescue(ctx, 321, robot.RescueRequest{OS: "linux"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Boot.Deactivate(ctx, 321, robot.BootProfileRescue); err != nil {
+			t.Fatal(err)
+		}
+		if err := recorder.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return rescue.Password
+	}
+
+	password := run(Record)
+	cassette, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{password, fake.Password} {
+		if strings.Contains(string(cassette), secret) {
+			t.Fatalf("expected %q to be scrubbed from the cassette:\n%s", secret, cassette)
+		}
+	}
+
+	fake.Close()
+	if password := run(Replay); password != "***" {
+		t.Fatalf("expected the scrubbed password to be replayed, got %q", password)
+	}
+
+	recorder, err := NewRecorder(path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := robot.NewClient("robot-test", "robot-test-password", robot.WithHTTPClient(&http.Client{Transport: recorder}), robot.WithRetries(0, 0))
+	if _, err := client.Boot.ActivateLinux(ctx, 321, robot.LinuxRequest{Dist: "Debian 12 base", Lang: "en"}); err == nil {
+		t.Fatal("expected a request not matching the cassette to fail")
+	}
+	if err := recorder.Close(); err == nil {
+		t.Fatal("expected unused interactions to be reported")
+	}
+}
+
+func TestRecorderIdentifiers(t *testing.T) {
+	const (
+		accountKey    = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKug+uI4ahKZNkrb7H06L56Xfm61OnTuMxbT+s/DOP4y account@example"
+		accountServer = 4711
+		accountIP     = "88.99.1.2"
+		accountNet    = "2a01:4f8:10a:1::"
+	)
+	fake := NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddServer(robot.Server{ServerNumber: accountServer, ServerName: "web", ServerIP: accountIP, ServerIPv6: accountNet, Rescue: true})
+	if _, err := fake.AddKey("account", accountKey); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	// run returns the server number and IP the client saw
+	run := func(mode RecorderMode) (int, string) {
+		recorder, err := NewRecorder(path, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := fake.Client(robot.WithHTTPClient(&http.Client{Transport: recorder}))
+
+		servers, err := client.Servers.List(ctx)
+		if err != nil || len(servers) != 1 {
+			t.Fatalf("unexpected servers: %+v, %v", servers, err)
+		}
+		server, err := client.Servers.Get(ctx, servers[0].ServerNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Firewall.Get(ctx, server.ServerIP); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Boot.ActivateRescue(ctx, server.ServerNumber, robot.RescueRequest{OS: "linux"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Keys.Create(ctx, robot.KeyCreateRequest{Name: "test", Data: testKey}); err != nil {
+			t.Fatal(err)
+		}
+		keys, err := client.Keys.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range keys {
+			if key.Name == "test" && key.Data != testKey {
+				t.Fatalf("expected the key of the client to be kept, got %+v", key)
+			}
+		}
+		if err := recorder.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return server.ServerNumber, server.ServerIP
+	}
+
+	if number, ip := run(Record); number != accountServer || ip != accountIP {
+		t.Fatalf("expected the client to see the account while recording, got %d and %s", number, ip)
+	}
+	cassette, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, identifier := range []string{"4711", accountIP, "2a01:4f8:10a:1", "AAAAIKug"} {
+		if strings.Contains(string(cassette), identifier) {
+			t.Fatalf("expected %q to be scrubbed from the cassette:\n%s", identifier, cassette)
+		}
+	}
+	if !strings.Contains(string(cassette), "2001:db8:0:1::") || !strings.Contains(string(cassette), "/boot/321/rescue") {
+		t.Fatalf("expected the stand-ins of the server in the cassette:\n%s", cassette)
+	}
+
+	fake.Close()
+	if number, ip := run(Replay); number != 321 || ip != "192.0.2.1" {
+		t.Fatalf("expected the stand-ins to be replayed, got %d and %s", number, ip)
+	}
+}
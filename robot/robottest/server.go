@@ -0,0 +1,141 @@
+package robottest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// AddServer adds a server to the account, with a disabled firewall, the reset types of a dedicated server
+// and its main IP. The linux boot profile is always available, the other profiles only if the
+// corresponding availability flag (Rescue, VNC, Windows, Plesk, CPanel) of server is set.
+func (s *Server) AddServer(server robot.Server) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if server.ServerName == "" {
+		server.ServerName = "server-" + strconv.Itoa(server.ServerNumber)
+	}
+	if server.Status == "" {
+		server.Status = "ready"
+	}
+	if server.IPs == nil && server.ServerIP != "" {
+		server.IPs = []string{server.ServerIP}
+	}
+	server.Reset = true
+	s.servers[server.ServerNumber] = &server
+
+	s.boots[server.ServerNumber] = newBootState(server)
+
+	resetTypes := robot.StringList{robot.ResetSoftware, robot.ResetHardware, robot.ResetManual}
+	if server.Wol {
+		resetTypes = append(resetTypes, robot.ResetPower)
+	}
+	s.resets[server.ServerNumber] = &robot.Reset{
+		ServerIP:        server.ServerIP,
+		ServerIPv6Net:   server.ServerIPv6,
+		ServerNumber:    server.ServerNumber,
+		Type:            resetTypes,
+		OperatingStatus: "not supported",
+	}
+
+	s.firewalls[server.ServerNumber] = &firewallState{
+		firewall: robot.Firewall{
+			ServerIP:     server.ServerIP,
+			ServerNumber: server.ServerNumber,
+			Status:       "disabled",
+			WhitelistHOS: true,
+			Port:         "main",
+			Rules:        robot.FirewallRules{Input: []robot.FirewallRule{}, Output: []robot.FirewallRule{}},
+		},
+	}
+
+	for _, ip := range server.IPs {
+		s.ips[ip] = &robot.IP{
+			IP:           ip,
+			ServerIP:     server.ServerIP,
+			ServerNumber: server.ServerNumber,
+			Gateway:      gateway(ip),
+			Mask:         26,
+			Broadcast:    broadcast(ip),
+		}
+	}
+}
+
+type serverDocument struct {
+	Server robot.Server `json:"server"`
+}
+
+// listedServer is a server as reported by GET /server, without availability flags.
+type listedServer struct {
+	ServerIP     string               `json:"server_ip"`
+	ServerIPv6   string               `json:"server_ipv6_net"`
+	ServerNumber int                  `json:"server_number"`
+	ServerName   string               `json:"server_name"`
+	Product      string               `json:"product"`
+	DataCenter   string               `json:"dc"`
+	Traffic      string               `json:"traffic"`
+	Status       string               `json:"status"`
+	Cancelled    bool                 `json:"cancelled"`
+	PaidUntil    string               `json:"paid_until"`
+	IPs          []string             `json:"ip"`
+	Subnets      []robot.ServerSubnet `json:"subnet"`
+}
+
+func (s *Server) listServers(w http.ResponseWriter, r *http.Request) {
+	numbers := make([]int, 0, len(s.servers))
+	for number := range s.servers {
+		numbers = append(numbers, number)
+	}
+	if len(numbers) == 0 {
+		writeError(w, http.StatusNotFound, "SERVER_NOT_FOUND", "No servers found")
+		return
+	}
+	sort.Ints(numbers)
+
+	response := make([]map[string]listedServer, 0, len(numbers))
+	for _, number := range numbers {
+		server := s.servers[number]
+		response = append(response, map[string]listedServer{"server": {
+			ServerIP:     server.ServerIP,
+			ServerIPv6:   server.ServerIPv6,
+			ServerNumber: server.ServerNumber,
+			ServerName:   server.ServerName,
+			Product:      server.Product,
+			DataCenter:   server.DataCenter,
+			Traffic:      server.Traffic,
+			Status:       server.Status,
+			Cancelled:    server.Cancelled,
+			PaidUntil:    server.PaidUntil,
+			IPs:          server.IPs,
+			Subnets:      server.Subnets,
+		}})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getServer(w http.ResponseWriter, r *http.Request) {
+	server, ok := s.serverByNumber(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, serverDocument{Server: *server})
+}
+
+func (s *Server) renameServer(w http.ResponseWriter, r *http.Request) {
+	server, ok := s.serverByNumber(w, r)
+	if !ok {
+		return
+	}
+
+	var input inputErrors
+	name := input.require(r, "server_name")
+	if input.write(w) {
+		return
+	}
+
+	server.ServerName = name
+	writeJSON(w, http.StatusOK, serverDocument{Server: *server})
+}
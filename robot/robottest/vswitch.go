@@ -0,0 +1,247 @@
+package robottest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// VLAN IDs Robot allows for vSwitches.
+const (
+	minVLAN = 4000
+	maxVLAN = 4091
+)
+
+var cancellationDatePattern = regexp.MustCompile(`^(now|\d{4}-\d{2}-\d{2})$`)
+
+// vSwitchState is a vSwitch. Servers being added stay "in process" until pendingReads reads of the vSwitch
+// are done, and further server changes are refused meanwhile.
+type vSwitchState struct {
+	vSwitch      robot.VSwitch
+	processing   bool
+	pendingReads int
+}
+
+// listedVSwitch is a vSwitch as reported by GET /vswitch, without servers, subnets and cloud networks.
+type listedVSwitch struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	VLAN      int    `json:"vlan"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+func (v *vSwitchState) read() robot.VSwitch {
+	if v.processing && consumeRead(&v.pendingReads) {
+		v.processing = false
+		for i := range v.vSwitch.Servers {
+			v.vSwitch.Servers[i].Status = "ready"
+		}
+	}
+	return v.vSwitch
+}
+
+// vSwitchByID resolves the {id} path value.
+func (s *Server) vSwitchByID(w http.ResponseWriter, r *http.Request) (*vSwitchState, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err == nil {
+		if vSwitch, ok := s.vSwitches[id]; ok {
+			return vSwitch, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "NOT_FOUND", "vSwitch not found")
+	return nil, false
+}
+
+// parseVSwitch validates the name and vlan parameters.
+func (s *Server) parseVSwitch(w http.ResponseWriter, r *http.Request, id int) (string, int, bool) {
+	var input inputErrors
+	name := input.require(r, "name")
+	vlanParam := input.require(r, "vlan")
+	vlan, err := strconv.Atoi(vlanParam)
+	if vlanParam != "" {
+		input.check(err == nil && vlan >= minVLAN && vlan <= maxVLAN, "vlan")
+	}
+	if input.write(w) {
+		return "", 0, false
+	}
+
+	for _, other := range s.vSwitches {
+		if other.vSwitch.ID != id && !other.vSwitch.Cancelled && other.vSwitch.VLAN == vlan {
+			writeError(w, http.StatusConflict, "VSWITCH_VLAN_NOT_UNIQUE", "The VLAN ID is already in use")
+			return "", 0, false
+		}
+	}
+	return name, vlan, true
+}
+
+func (s *Server) listVSwitches(w http.ResponseWriter, r *http.Request) {
+	ids := make([]int, 0, len(s.vSwitches))
+	for id := range s.vSwitches {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	response := make([]listedVSwitch, 0, len(ids))
+	for _, id := range ids {
+		vSwitch := s.vSwitches[id].vSwitch
+		response = append(response, listedVSwitch{
+			ID:        vSwitch.ID,
+			Name:      vSwitch.Name,
+			VLAN:      vSwitch.VLAN,
+			Cancelled: vSwitch.Cancelled,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) createVSwitch(w http.ResponseWriter, r *http.Request) {
+	name, vlan, ok := s.parseVSwitch(w, r, 0)
+	if !ok {
+		return
+	}
+
+	id := s.nextVSwitchID
+	s.nextVSwitchID++
+	s.vSwitches[id] = &vSwitchState{vSwitch: robot.VSwitch{
+		ID:            id,
+		Name:          name,
+		VLAN:          vlan,
+		Servers:       []robot.VSwitchServer{},
+		Subnets:       []robot.VSwitchSubnet{},
+		CloudNetworks: []robot.VSwitchCloudNetwork{},
+	}}
+	writeJSON(w, http.StatusCreated, s.vSwitches[id].vSwitch)
+}
+
+func (s *Server) getVSwitch(w http.ResponseWriter, r *http.Request) {
+	vSwitch, ok := s.vSwitchByID(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, vSwitch.read())
+}
+
+func (s *Server) updateVSwitch(w http.ResponseWriter, r *http.Request) {
+	vSwitch, ok := s.vSwitchByID(w, r)
+	if !ok {
+		return
+	}
+	name, vlan, ok := s.parseVSwitch(w, r, vSwitch.vSwitch.ID)
+	if !ok {
+		return
+	}
+
+	vSwitch.vSwitch.Name = name
+	vSwitch.vSwitch.VLAN = vlan
+	writeJSON(w, http.StatusCreated, nil)
+}
+
+func (s *Server) cancelVSwitch(w http.ResponseWriter, r *http.Request) {
+	vSwitch, ok := s.vSwitchByID(w, r)
+	if !ok {
+		return
+	}
+
+	var input inputErrors
+	date := input.require(r, "cancellation_date")
+	if date != "" {
+		input.check(cancellationDatePattern.MatchString(date), "cancellation_date")
+	}
+	if input.write(w) {
+		return
+	}
+
+	if vSwitch.vSwitch.Cancelled {
+		writeError(w, http.StatusConflict, "CONFLICT", "The vSwitch is already cancelled")
+		return
+	}
+	vSwitch.vSwitch.Cancelled = true
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// vSwitchServers resolves the server parameters, given as server numbers or main IPs.
+func (s *Server) vSwitchServers(w http.ResponseWriter, r *http.Request) ([]*robot.Server, bool) {
+	ids := formList(r, "server")
+	if len(ids) == 0 {
+		(&inputErrors{missing: []string{"server"}}).write(w)
+		return nil, false
+	}
+
+	servers := make([]*robot.Server, 0, len(ids))
+	for _, id := range ids {
+		server, ok := s.serverByID(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "SERVER_NOT_FOUND", "Server not found")
+			return nil, false
+		}
+		servers = append(servers, server)
+	}
+	return servers, true
+}
+
+func (s *Server) addVSwitchServers(w http.ResponseWriter, r *http.Request) {
+	vSwitch, ok := s.vSwitchByID(w, r)
+	if !ok {
+		return
+	}
+	servers, ok := s.vSwitchServers(w, r)
+	if !ok {
+		return
+	}
+	if vSwitch.processing {
+		writeError(w, http.StatusConflict, "VSWITCH_IN_PROCESS", "The vSwitch is currently in process")
+		return
+	}
+
+	for _, server := range servers {
+		for _, attached := range vSwitch.vSwitch.Servers {
+			if attached.ServerNumber == server.ServerNumber {
+				writeError(w, http.StatusConflict, "VSWITCH_SERVER_ALREADY_ASSIGNED", "The server is already assigned to the vSwitch")
+				return
+			}
+		}
+	}
+
+	for _, server := range servers {
+		vSwitch.vSwitch.Servers = append(vSwitch.vSwitch.Servers, robot.VSwitchServer{
+			ServerNumber:  server.ServerNumber,
+			ServerIP:      server.ServerIP,
+			ServerIPv6Net: server.ServerIPv6,
+			Status:        "in process",
+		})
+	}
+	vSwitch.processing = true
+	vSwitch.pendingReads = s.processingReads
+	writeJSON(w, http.StatusCreated, nil)
+}
+
+func (s *Server) removeVSwitchServers(w http.ResponseWriter, r *http.Request) {
+	vSwitch, ok := s.vSwitchByID(w, r)
+	if !ok {
+		return
+	}
+	servers, ok := s.vSwitchServers(w, r)
+	if !ok {
+		return
+	}
+	if vSwitch.processing {
+		writeError(w, http.StatusConflict, "VSWITCH_IN_PROCESS", "The vSwitch is currently in process")
+		return
+	}
+
+	remove := make(map[int]bool, len(servers))
+	for _, server := range servers {
+		remove[server.ServerNumber] = true
+	}
+	attached := make([]robot.VSwitchServer, 0, len(vSwitch.vSwitch.Servers))
+	for _, server := range vSwitch.vSwitch.Servers {
+		if !remove[server.ServerNumber] {
+			attached = append(attached, server)
+		}
+	}
+	vSwitch.vSwitch.Servers = attached
+	writeJSON(w, http.StatusCreated, nil)
+}
@@ -0,0 +1,153 @@
+package robot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultMaxConcurrentRequests is the number of requests in flight of a client built without WithScheduler.
+const DefaultMaxConcurrentRequests = 4
+
+// defaultRequestBudgets are the requests per hour the provider allows itself per endpoint family,
+// kept below the limits Robot enforces. Families not listed use defaultRequestBudget.
+var defaultRequestBudgets = map[string]int{
+	"boot":     200,
+	"firewall": 200,
+	"reset":    50,
+	"server":   200,
+}
+
+const defaultRequestBudget = 500
+
+// requestScheduler queues the requests of all resources of a provider instance. It caps the number of
+// requests in flight and spreads the requests of each endpoint family with a token bucket, so that
+// Terraform's parallel operations do not burst into Robot's rate limits.
+type requestScheduler struct {
+	slots chan struct{}
+
+	mu      sync.Mutex
+	budgets map[string]int
+	buckets map[string]*tokenBucket
+}
+
+func newRequestScheduler(maxConcurrentRequests int, budgets map[string]int) *requestScheduler {
+	merged := make(map[string]int, len(defaultRequestBudgets)+len(budgets))
+	for family, budget := range defaultRequestBudgets {
+		merged[family] = budget
+	}
+	for family, budget := range budgets {
+		merged[family] = budget
+	}
+
+	return &requestScheduler{
+		slots:   make(chan struct{}, maxConcurrentRequests),
+		budgets: merged,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// endpointFamily returns the first segment of a webservice path, e.g. "boot" for /boot/123/rescue.
+func endpointFamily(path string) string {
+	family, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	family, _, _ = strings.Cut(family, "?")
+	return family
+}
+
+func (s *requestScheduler) bucket(family string) *tokenBucket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[family]
+	if !ok {
+		budget, ok := s.budgets[family]
+		if !ok {
+			budget = defaultRequestBudget
+		}
+		bucket = newTokenBucket(budget)
+		s.buckets[family] = bucket
+	}
+	return bucket
+}
+
+// acquire blocks until the request may be sent and returns the function releasing its slot.
+func (s *requestScheduler) acquire(ctx context.Context, family string) (func(), error) {
+	start := time.Now()
+
+	if err := s.bucket(family).wait(ctx); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	tflog.Debug(ctx, "scheduled Hetzner webservice request", map[string]interface{}{
+		"endpoint": family,
+		"wait_ms":  time.Since(start).Milliseconds(),
+	})
+
+	return func() { <-s.slots }, nil
+}
+
+// tokenBucket allows a tenth of the hourly budget at once and refills continuously over the hour.
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(perHour int) *tokenBucket {
+	capacity := float64(perHour) / 10
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{
+		capacity: capacity,
+		tokens:   capacity,
+		rate:     float64(perHour) / time.Hour.Seconds(),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait until it is covered.
+// Tokens may go negative, which queues the callers in arrival order.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if err := sleepContext(ctx, b.reserve()); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type ServerSubnet struct {
+	IP   string `json:"ip"`
+	Mask string `json:"mask"`
+}
+
+// Server is a dedicated server. The availability flags and the linked storage box are only reported
+// by ServerService.Get, not by ServerService.List.
+type Server struct {
+	ServerIP         string         `json:"server_ip"`
+	ServerIPv6       string         `json:"server_ipv6_net"`
+	ServerNumber     int            `json:"server_number"`
+	ServerName       string         `json:"server_name"`
+	Product          string         `json:"product"`
+	DataCenter       string         `json:"dc"`
+	Traffic          string         `json:"traffic"`
+	Status           string         `json:"status"`
+	Cancelled        bool           `json:"cancelled"`
+	PaidUntil        string         `json:"paid_until"`
+	IPs              []string       `json:"ip"`
+	Subnets          []ServerSubnet `json:"subnet"`
+	LinkedStoragebox int            `json:"linked_storagebox"`
+
+	Reset   bool `json:"reset"`
+	Rescue  bool `json:"rescue"`
+	VNC     bool `json:"vnc"`
+	Windows bool `json:"windows"`
+	Plesk   bool `json:"plesk"`
+	CPanel  bool `json:"cpanel"`
+	Wol     bool `json:"wol"`
+	HotSwap bool `json:"hot_swap"`
+}
+
+type serverResponse struct {
+	Server Server `json:"server"`
+}
+
+// ServerService handles the /server endpoints.
+type ServerService struct {
+	client *Client
+}
+
+// List returns all servers of the account.
+func (s *ServerService) List(ctx context.Context) ([]Server, error) {
+	var response []serverResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/server", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	servers := make([]Server, 0, len(response))
+	for _, item := range response {
+		servers = append(servers, item.Server)
+	}
+	return servers, nil
+}
+
+// Get returns a server by number, including its availability flags. With the read cache enabled, servers
+// missing from the server list are reported as not found without a request.
+func (s *ServerService) Get(ctx context.Context, serverNumber int) (*Server, error) {
+	response := serverResponse{}
+	if err := s.client.getCached(ctx, "server", strconv.Itoa(serverNumber), fmt.Sprintf("/server/%d", serverNumber), &response); err != nil {
+		return nil, err
+	}
+	return &response.Server, nil
+}
+
+// Rename changes the name of a server.
+func (s *ServerService) Rename(ctx context.Context, serverNumber int, name string) (*Server, error) {
+	data := url.Values{}
+	data.Set("server_name", name)
+
+	response := serverResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/server/%d", serverNumber), data, &response); err != nil {
+		return nil, err
+	}
+	return &response.Server, nil
+}
@@ -0,0 +1,150 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#storage-box
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// StorageBox is a storage box. Quotas, usage and service flags are only reported by
+// StorageBoxService.Get, not by StorageBoxService.List.
+type StorageBox struct {
+	ID                   int    `json:"id"`
+	Login                string `json:"login"`
+	Name                 string `json:"name"`
+	Product              string `json:"product"`
+	Cancelled            bool   `json:"cancelled"`
+	Locked               bool   `json:"locked"`
+	Location             string `json:"location"`
+	LinkedServer         int    `json:"linked_server"`
+	PaidUntil            string `json:"paid_until"`
+	DiskQuota            int    `json:"disk_quota"`
+	DiskUsage            int    `json:"disk_usage"`
+	DiskUsageData        int    `json:"disk_usage_data"`
+	DiskUsageSnapshots   int    `json:"disk_usage_snapshots"`
+	Webdav               bool   `json:"webdav"`
+	Samba                bool   `json:"samba"`
+	SSH                  bool   `json:"ssh"`
+	ExternalReachability bool   `json:"external_reachability"`
+	ZFS                  bool   `json:"zfs"`
+	Server               string `json:"server"`
+	HostSystem           string `json:"host_system"`
+}
+
+type storageBoxResponse struct {
+	StorageBox StorageBox `json:"storagebox"`
+}
+
+// StorageBoxSubaccount is a sub-account of a storage box. Password is only set when the sub-account is created.
+type StorageBoxSubaccount struct {
+	Username             string `json:"username"`
+	AccountID            string `json:"accountid"`
+	Server               string `json:"server"`
+	Homedirectory        string `json:"homedirectory"`
+	Samba                bool   `json:"samba"`
+	SSH                  bool   `json:"ssh"`
+	ExternalReachability bool   `json:"external_reachability"`
+	Webdav               bool   `json:"webdav"`
+	Readonly             bool   `json:"readonly"`
+	CreateTime           string `json:"createtime"`
+	Comment              string `json:"comment"`
+	Password             string `json:"password"`
+}
+
+type storageBoxSubaccountResponse struct {
+	Subaccount StorageBoxSubaccount `json:"subaccount"`
+}
+
+type StorageBoxSubaccountRequest struct {
+	Homedirectory        string
+	Samba                bool
+	SSH                  bool
+	ExternalReachability bool
+	Webdav               bool
+	Readonly             bool
+	Comment              string
+}
+
+func (r StorageBoxSubaccountRequest) values() url.Values {
+	data := url.Values{}
+	data.Set("homedirectory", r.Homedirectory)
+	data.Set("samba", boolString(r.Samba))
+	data.Set("ssh", boolString(r.SSH))
+	data.Set("external_reachability", boolString(r.ExternalReachability))
+	data.Set("webdav", boolString(r.Webdav))
+	data.Set("readonly", boolString(r.Readonly))
+	setIfNotEmpty(data, "comment", r.Comment)
+	return data
+}
+
+// StorageBoxService handles the /storagebox endpoints.
+type StorageBoxService struct {
+	client *Client
+}
+
+func (s *StorageBoxService) List(ctx context.Context) ([]StorageBox, error) {
+	var response []storageBoxResponse
+	if err := s.client.Do(ctx, http.MethodGet, "/storagebox", nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	storageBoxes := make([]StorageBox, 0, len(response))
+	for _, item := range response {
+		storageBoxes = append(storageBoxes, item.StorageBox)
+	}
+	return storageBoxes, nil
+}
+
+func (s *StorageBoxService) Get(ctx context.Context, id int) (*StorageBox, error) {
+	response := storageBoxResponse{}
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/storagebox/%d", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.StorageBox, nil
+}
+
+// ResetPassword sets a new random password and returns it.
+func (s *StorageBoxService) ResetPassword(ctx context.Context, id int) (string, error) {
+	response := struct {
+		Password string `json:"password"`
+	}{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/storagebox/%d/password", id), nil, &response); err != nil {
+		return "", err
+	}
+	return response.Password, nil
+}
+
+func (s *StorageBoxService) ListSubaccounts(ctx context.Context, id int) ([]StorageBoxSubaccount, error) {
+	var response []storageBoxSubaccountResponse
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/storagebox/%d/subaccount", id), nil, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	subaccounts := make([]StorageBoxSubaccount, 0, len(response))
+	for _, item := range response {
+		subaccounts = append(subaccounts, item.Subaccount)
+	}
+	return subaccounts, nil
+}
+
+// CreateSubaccount creates a sub-account, the returned value holds its generated username and password.
+func (s *StorageBoxService) CreateSubaccount(ctx context.Context, id int, request StorageBoxSubaccountRequest) (*StorageBoxSubaccount, error) {
+	response := storageBoxSubaccountResponse{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/storagebox/%d/subaccount", id), request.values(), &response); err != nil {
+		return nil, err
+	}
+	return &response.Subaccount, nil
+}
+
+func (s *StorageBoxService) DeleteSubaccount(ctx context.Context, id int, username string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/storagebox/%d/subaccount/%s", id, username), nil, nil)
+}
@@ -0,0 +1,68 @@
+package robot
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// StringList is a webservice attribute that is either a single value or a list of values. Boot profiles
+// for example report the available distributions as a list while inactive and the installed one once
+// active. Numbers are kept in their JSON notation ("64").
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*l = nil
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		list := make(StringList, 0, len(items))
+		for _, item := range items {
+			list = append(list, rawString(item))
+		}
+		*l = list
+		return nil
+	default:
+		*l = StringList{rawString(data)}
+		return nil
+	}
+}
+
+// First returns the first value, or "" for an empty list.
+func (l StringList) First() string {
+	if len(l) == 0 {
+		return ""
+	}
+	return l[0]
+}
+
+// Contains reports whether value is one of the values.
+func (l StringList) Contains(value string) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func rawString(data json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,116 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#vswitch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type VSwitchServer struct {
+	ServerNumber  int    `json:"server_number"`
+	ServerIP      string `json:"server_ip"`
+	ServerIPv6Net string `json:"server_ipv6_net"`
+	Status        string `json:"status"` // ready, in process or failed
+}
+
+type VSwitchSubnet struct {
+	IP      string `json:"ip"`
+	Mask    int    `json:"mask"`
+	Gateway string `json:"gateway"`
+}
+
+type VSwitchCloudNetwork struct {
+	ID      int    `json:"id"`
+	IP      string `json:"ip"`
+	Mask    int    `json:"mask"`
+	Gateway string `json:"gateway"`
+}
+
+// VSwitch is a vSwitch. Servers, subnets and cloud networks are only reported by VSwitchService.Get,
+// not by VSwitchService.List.
+type VSwitch struct {
+	ID            int                   `json:"id"`
+	Name          string                `json:"name"`
+	VLAN          int                   `json:"vlan"`
+	Cancelled     bool                  `json:"cancelled"`
+	Servers       []VSwitchServer       `json:"server"`
+	Subnets       []VSwitchSubnet       `json:"subnet"`
+	CloudNetworks []VSwitchCloudNetwork `json:"cloud_network"`
+}
+
+type VSwitchRequest struct {
+	Name string
+	VLAN int
+}
+
+func (r VSwitchRequest) values() url.Values {
+	data := url.Values{}
+	data.Set("name", r.Name)
+	data.Set("vlan", strconv.Itoa(r.VLAN))
+	return data
+}
+
+func serverValues(serverNumbers []int) url.Values {
+	data := url.Values{}
+	for _, serverNumber := range serverNumbers {
+		data.Add("server", strconv.Itoa(serverNumber))
+	}
+	return data
+}
+
+// VSwitchService handles the /vswitch endpoints.
+type VSwitchService struct {
+	client *Client
+}
+
+func (s *VSwitchService) List(ctx context.Context) ([]VSwitch, error) {
+	var vSwitches []VSwitch
+	if err := s.client.Do(ctx, http.MethodGet, "/vswitch", nil, &vSwitches); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return vSwitches, nil
+}
+
+// Get returns a vSwitch by ID. With the read cache enabled, vSwitches missing from the vSwitch list are
+// reported as not found without a request.
+func (s *VSwitchService) Get(ctx context.Context, id int) (*VSwitch, error) {
+	vSwitch := VSwitch{}
+	if err := s.client.getCached(ctx, "vswitch", strconv.Itoa(id), fmt.Sprintf("/vswitch/%d", id), &vSwitch); err != nil {
+		return nil, err
+	}
+	return &vSwitch, nil
+}
+
+func (s *VSwitchService) Create(ctx context.Context, request VSwitchRequest) (*VSwitch, error) {
+	vSwitch := VSwitch{}
+	if err := s.client.Do(ctx, http.MethodPost, "/vswitch", request.values(), &vSwitch); err != nil {
+		return nil, err
+	}
+	return &vSwitch, nil
+}
+
+func (s *VSwitchService) Update(ctx context.Context, id int, request VSwitchRequest) error {
+	return s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/vswitch/%d", id), request.values(), nil)
+}
+
+// Cancel cancels the vSwitch at the given date (YYYY-MM-DD) or "now".
+func (s *VSwitchService) Cancel(ctx context.Context, id int, cancellationDate string) error {
+	data := url.Values{}
+	data.Set("cancellation_date", cancellationDate)
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/vswitch/%d", id), data, nil)
+}
+
+func (s *VSwitchService) AddServers(ctx context.Context, id int, serverNumbers []int) error {
+	return s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/vswitch/%d/server", id), serverValues(serverNumbers), nil)
+}
+
+func (s *VSwitchService) RemoveServers(ctx context.Context, id int, serverNumbers []int) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/vswitch/%d/server", id), serverValues(serverNumbers), nil)
+}